@@ -0,0 +1,111 @@
+// godoo/attachments_test.go
+package godoo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeAttachments answers ir.attachment calls like Odoo: create decodes `datas` and stores
+// its checksum and size, read returns them.
+func fakeAttachments(t *testing.T, srv *fakeOdoo) *fakeRecords {
+	t.Helper()
+	store := newFakeRecords()
+	srv.handle = func(call fakeCall) (interface{}, error) {
+		if call.Model == string(ModelIrAttachment) && call.Method == "create" {
+			for _, v := range call.Args[0].([]interface{}) {
+				vals := v.(map[string]interface{})
+				raw, err := base64.StdEncoding.DecodeString(fmt.Sprint(vals["datas"]))
+				if err != nil {
+					return nil, &fakeFault{"odoo.exceptions.UserError", "bad base64"}
+				}
+				sum := sha1.Sum(raw)
+				vals["checksum"] = hex.EncodeToString(sum[:])
+				vals["file_size"] = int64(len(raw))
+			}
+		}
+		return store.handle(call)
+	}
+	return store
+}
+
+func TestAttachmentRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"xmlrpc", nil},
+		{"jsonrpc", []Option{WithTransport(TransportJSONRPC)}},
+		{"web", []Option{WithTransport(TransportWebSession)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeOdoo(t, "s3cret")
+			store := fakeAttachments(t, srv)
+			srv.handleWeb("/web/content/", func(w http.ResponseWriter, r *http.Request) {
+				var id int64
+				fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/web/content/"), "%d", &id)
+				raw, _ := base64.StdEncoding.DecodeString(fmt.Sprint(store.get(id)["datas"]))
+				w.Write(raw)
+			})
+			c := srv.client(t, tt.opts...)
+			ctx := context.Background()
+
+			content := strings.Repeat("%PDF-1.7 contract ", 1000)
+			att, err := c.UploadAttachment(ctx, ModelResPartner, 7, "contract.pdf", strings.NewReader(content))
+			if err != nil {
+				t.Fatalf("UploadAttachment: %v", err)
+			}
+			if att.Size != int64(len(content)) || att.Mimetype != "application/pdf" {
+				t.Errorf("attachment = %+v, want %d bytes of application/pdf", att, len(content))
+			}
+			if rec := store.get(att.ID); rec["res_model"] != "res.partner" || rec["res_id"] != int64(7) {
+				t.Errorf("stored attachment = %v, want it attached to res.partner(7)", rec)
+			}
+
+			var out bytes.Buffer
+			if _, err := c.DownloadAttachment(ctx, att.ID, &out); err != nil {
+				t.Fatalf("DownloadAttachment: %v", err)
+			}
+			if out.String() != content {
+				t.Errorf("downloaded %d bytes, want the %d uploaded", out.Len(), len(content))
+			}
+
+			// Content that does not match Odoo's checksum is reported.
+			store.set(att.ID, "checksum", "0000")
+			if _, err := c.DownloadAttachment(ctx, att.ID, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "checksum") {
+				t.Errorf("DownloadAttachment with a wrong checksum: error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDownloadBinaryFieldKeepsCallerOptions(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	store := newFakeRecords(map[string]interface{}{"image_1920": base64.StdEncoding.EncodeToString([]byte("png"))})
+	srv.handle = store.handle
+	c := srv.client(t)
+
+	// A slice with spare capacity: appending to it must not write into the caller's array.
+	options := make([]*Options, 1, 4)
+	options[0] = Lang("es_VE")
+	spare := options[:2]
+	var out bytes.Buffer
+	n, err := c.DownloadBinaryField(context.Background(), ModelProductTemplate, 1, "image_1920", &out, options...)
+	if err != nil || n != 3 || out.String() != "png" {
+		t.Fatalf("DownloadBinaryField = %d, %q, %v; want the 3 bytes", n, out.String(), err)
+	}
+	if spare[1] != nil {
+		t.Errorf("DownloadBinaryField wrote %+v past the caller's options", spare[1])
+	}
+	if kwargs := srv.received()[0].Kwargs; kwargs["context"].(map[string]interface{})["bin_size"] != false {
+		t.Errorf("read context = %v, want bin_size false", kwargs["context"])
+	}
+}
@@ -0,0 +1,85 @@
+// godoo/auth.go
+package godoo
+
+import "fmt"
+
+// CredentialType identifies which kind of secret the client presents to Odoo.
+type CredentialType string
+
+const (
+	// CredentialPassword authenticates with the user's login password (the historical default).
+	CredentialPassword CredentialType = "password"
+	// CredentialAPIKey authenticates with an Odoo API key (Preferences > Account Security).
+	// Over XML-RPC the key is sent in place of the password.
+	CredentialAPIKey CredentialType = "api_key"
+	// CredentialBearer authenticates with an API key sent as an `Authorization: Bearer` header.
+	// It is only valid with the JSON-2 transport of Odoo 17+.
+	CredentialBearer CredentialType = "bearer"
+)
+
+// WithAPIKey configura el cliente para autenticarse con una API key de Odoo
+// en lugar de la contraseña pasada a New.
+func WithAPIKey(key string) Option {
+	return func(c *OdooClient) {
+		c.credentialType = CredentialAPIKey
+		c.credentials = StaticCredential(key)
+	}
+}
+
+// WithBearerToken configura el cliente para enviar la API key como cabecera
+// `Authorization: Bearer` contra los endpoints JSON-2 (/json/2/<model>/<method>).
+// Si no se indica otro transporte con WithTransport, se selecciona TransportJSON2.
+func WithBearerToken(token string) Option {
+	return func(c *OdooClient) {
+		c.credentialType = CredentialBearer
+		c.credentials = StaticCredential(token)
+	}
+}
+
+// WithUID establece el ID de usuario de Odoo ya conocido, evitando la llamada
+// `common.authenticate` al conectar. Útil con API keys cuyo usuario es fijo.
+func WithUID(uid int64) Option {
+	return func(c *OdooClient) {
+		c.knownUID = uid
+	}
+}
+
+// validateCredentials checks that the credential type and transport chosen through
+// the functional options can be combined, filling in the transport when it was left implicit.
+func (c *OdooClient) validateCredentials() error {
+	if c.credentialType == "" {
+		c.credentialType = CredentialPassword
+	}
+	if c.transport == "" {
+		if c.credentialType == CredentialBearer {
+			c.transport = TransportJSON2
+		} else {
+			c.transport = TransportXMLRPC
+		}
+	}
+
+	switch c.credentialType {
+	case CredentialPassword, CredentialAPIKey, CredentialBearer:
+	default:
+		return fmt.Errorf("godoo: unknown credential type %q", c.credentialType)
+	}
+	switch c.transport {
+	case TransportXMLRPC, TransportJSON2, TransportWebSession, TransportJSONRPC:
+	default:
+		return fmt.Errorf("godoo: unknown transport %q", c.transport)
+	}
+
+	if c.transport == TransportJSON2 && c.credentialType == CredentialPassword {
+		return fmt.Errorf("godoo: the %s transport requires an API key (WithAPIKey or WithBearerToken), not a password", TransportJSON2)
+	}
+	if c.credentialType == CredentialBearer && c.transport != TransportJSON2 {
+		return fmt.Errorf("godoo: bearer tokens are only supported by the %s transport", TransportJSON2)
+	}
+	if c.transport == TransportWebSession && c.credentialType != CredentialPassword {
+		return fmt.Errorf("godoo: the %s transport requires a password; Odoo does not accept API keys for web sessions", TransportWebSession)
+	}
+	if c.knownUID < 0 {
+		return fmt.Errorf("godoo: invalid uid %d", c.knownUID)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+// godoo/batch.go
+package godoo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Defaults of WithBatchSize and WithConcurrency.
+const (
+	DefaultBatchSize   = 1000
+	DefaultConcurrency = 1
+)
+
+// WithBatchSize establece el número máximo de ids enviados en una sola llamada por Read,
+// ReadWithLimit, Update, Delete y UpdateMultiple, que dividen listas más largas en lotes.
+// Por defecto DefaultBatchSize. Odoo aplica cada lote de forma atómica, pero no la lista
+// entera: si un lote de escritura falla, los anteriores no se deshacen. Un valor <= 0
+// desactiva la división, enviando cada lista en una sola llamada.
+func WithBatchSize(n int) Option {
+	return func(c *OdooClient) {
+		c.batchSize = n
+	}
+}
+
+// WithConcurrency establece cuántas llamadas de una operación por lotes (lotes de Read,
+// Update, Delete o escrituras de UpdateMultiple) pueden estar en curso a la vez (por
+// defecto DefaultConcurrency, es decir, en serie).
+func WithConcurrency(n int) Option {
+	return func(c *OdooClient) {
+		c.concurrency = n
+	}
+}
+
+// orderIDs sorts ids by options.Order with a `search` restricted to them, and returns the
+// page selected by options.Offset and options.Limit. Archived records are kept, as `read`
+// returns them.
+func (c *OdooClient) orderIDs(ctx context.Context, model Model, ids []int64, options *Options) ([]int64, error) {
+	kwargs := MergeOptions(&Options{
+		Context: options.Context,
+		Limit:   options.Limit,
+		Offset:  options.Offset,
+		Order:   options.Order,
+	}, ActiveTest(false)).ToRPC()
+	var sorted []int64
+	err := c.executeRPC(ctx, string(model), "search", []interface{}{Domain{{"id", "in", ids}}.ToRPC()}, kwargs, &sorted)
+	if err != nil {
+		return nil, err
+	}
+	return sorted, nil
+}
+
+// pageIDs returns the ids selected by offset and limit (limit <= 0 meaning no limit).
+func pageIDs(ids []int64, offset, limit int) []int64 {
+	if offset > 0 {
+		if offset >= len(ids) {
+			return ids[:0]
+		}
+		ids = ids[offset:]
+	}
+	if limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
+	}
+	return ids
+}
+
+// chunkIDs splits ids into slices of at most size ids (a single slice if size <= 0).
+func chunkIDs(ids []int64, size int) [][]int64 {
+	if size <= 0 || len(ids) <= size {
+		return [][]int64{ids}
+	}
+	chunks := make([][]int64, 0, (len(ids)+size-1)/size)
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunks = append(chunks, ids[start:end:end])
+	}
+	return chunks
+}
+
+// runChunks calls fn for every chunk with the client's concurrency and returns the first
+// error in chunk order. Once a chunk fails the remaining ones are not started and those in
+// flight are cancelled. A single chunk is run directly.
+func (c *OdooClient) runChunks(ctx context.Context, chunks [][]int64, fn func(ctx context.Context, i int, chunk []int64) error) error {
+	if len(chunks) == 1 {
+		return fn(ctx, 0, chunks[0])
+	}
+	chunkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(chunks))
+	c.runConcurrently(chunkCtx, len(chunks), func(i int) {
+		if err := fn(chunkCtx, i, chunks[i]); err != nil {
+			errs[i] = err
+			cancel()
+		}
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// Prefer the failure that triggered the cancellation over the chunks it interrupted.
+	var first error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, context.Canceled) {
+			return err
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// readChunks calls `read` on ids in chunks and returns the records in the order of ids.
+func (c *OdooClient) readChunks(ctx context.Context, model Model, ids []int64, fields []string, kwargs map[string]interface{}) ([]map[string]interface{}, error) {
+	chunks := chunkIDs(ids, c.batchSize)
+	results := make([][]map[string]interface{}, len(chunks))
+	err := c.runChunks(ctx, chunks, func(ctx context.Context, i int, chunk []int64) error {
+		return c.executeRPC(ctx, string(model), "read", []interface{}{chunk, fields}, kwargs, &results[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 1 {
+		return results[0], nil
+	}
+	total := 0
+	for _, r := range results {
+		total += len(r)
+	}
+	records := make([]map[string]interface{}, 0, total)
+	for _, r := range results {
+		records = append(records, r...)
+	}
+	return records, nil
+}
+
+// boolChunks runs a method returning a boolean (write, unlink) on ids in chunks of the
+// batch size and reports whether every call returned true.
+func (c *OdooClient) boolChunks(ctx context.Context, ids []int64, call func(ctx context.Context, chunk []int64, reply *bool) error) (bool, error) {
+	chunks := chunkIDs(ids, c.batchSize)
+	replies := make([]bool, len(chunks))
+	err := c.runChunks(ctx, chunks, func(ctx context.Context, i int, chunk []int64) error {
+		return call(ctx, chunk, &replies[i])
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, ok := range replies {
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// runConcurrently calls fn(0) ... fn(n-1) with at most the client's concurrency calls in
+// flight, and waits for them. Calls not started yet when ctx is done are skipped.
+func (c *OdooClient) runConcurrently(ctx context.Context, n int, fn func(i int)) {
+	limit := c.concurrency
+	if limit <= 0 {
+		limit = 1
+	}
+	if limit > n {
+		limit = n
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// writeGroup is a set of records receiving the same values.
+type writeGroup struct {
+	ids  []int64
+	data Data
+}
+
+// groupWrites groups the records of a per-id write map by identical values, so each group
+// can be written with a single write call. Groups and their ids are sorted by id.
+func groupWrites(updates map[int64]Data) []writeGroup {
+	ids := make([]int64, 0, len(updates))
+	for id := range updates {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var groups []writeGroup
+	index := make(map[string]int)
+	for _, id := range ids {
+		data := updates[id]
+		raw, err := json.Marshal(data.ToRPC())
+		if err != nil {
+			// Values that cannot be compared are written on their own.
+			groups = append(groups, writeGroup{ids: []int64{id}, data: data})
+			continue
+		}
+		if i, ok := index[string(raw)]; ok {
+			groups[i].ids = append(groups[i].ids, id)
+			continue
+		}
+		index[string(raw)] = len(groups)
+		groups = append(groups, writeGroup{ids: []int64{id}, data: data})
+	}
+	return groups
+}
@@ -0,0 +1,119 @@
+// godoo/batch_test.go
+package godoo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunkIDs(t *testing.T) {
+	ids := []int64{1, 2, 3, 4, 5}
+	tests := []struct {
+		name string
+		ids  []int64
+		size int
+		want [][]int64
+	}{
+		{"no batching", ids, 0, [][]int64{ids}},
+		{"negative size", ids, -1, [][]int64{ids}},
+		{"size larger than list", ids, 10, [][]int64{ids}},
+		{"size equal to list", ids, 5, [][]int64{ids}},
+		{"exact multiple", []int64{1, 2, 3, 4}, 2, [][]int64{{1, 2}, {3, 4}}},
+		{"remainder", ids, 2, [][]int64{{1, 2}, {3, 4}, {5}}},
+		{"size one", []int64{1, 2, 3}, 1, [][]int64{{1}, {2}, {3}}},
+		{"empty", []int64{}, 2, [][]int64{{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chunkIDs(tt.ids, tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunkIDs(%v, %d) = %v, want %v", tt.ids, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkIDsDoNotShareCapacity(t *testing.T) {
+	chunks := chunkIDs([]int64{1, 2, 3, 4}, 2)
+	_ = append(chunks[0], 99)
+	if chunks[1][0] != 3 {
+		t.Errorf("appending to a chunk overwrote the next one: %v", chunks)
+	}
+}
+
+func TestPageIDs(t *testing.T) {
+	ids := []int64{10, 20, 30, 40, 50}
+	tests := []struct {
+		name          string
+		offset, limit int
+		want          []int64
+	}{
+		{"no offset nor limit", 0, 0, ids},
+		{"limit", 0, 2, []int64{10, 20}},
+		{"offset", 3, 0, []int64{40, 50}},
+		{"offset and limit", 1, 3, []int64{20, 30, 40}},
+		{"limit past the end", 3, 10, []int64{40, 50}},
+		{"limit equal to the list", 0, 5, ids},
+		{"offset at the end", 5, 0, []int64{}},
+		{"offset past the end", 8, 2, []int64{}},
+		{"negative values are ignored", -1, -1, ids},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageIDs(ids, tt.offset, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pageIDs(%v, %d, %d) = %v, want %v", ids, tt.offset, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupWrites(t *testing.T) {
+	tests := []struct {
+		name    string
+		updates map[int64]Data
+		want    [][]int64
+	}{
+		{"empty", map[int64]Data{}, [][]int64{}},
+		{
+			name:    "identical data share a write",
+			updates: map[int64]Data{3: {"active": false}, 1: {"active": false}, 2: {"active": false}},
+			want:    [][]int64{{1, 2, 3}},
+		},
+		{
+			name:    "groups in order of their first id",
+			updates: map[int64]Data{5: {"name": "B"}, 1: {"active": false}, 4: {"name": "A"}, 2: {"active": false}},
+			want:    [][]int64{{1, 2}, {4}, {5}},
+		},
+		{
+			name:    "key order does not matter",
+			updates: map[int64]Data{1: {"name": "A", "active": true}, 2: {"active": true, "name": "A"}},
+			want:    [][]int64{{1, 2}},
+		},
+		{
+			name:    "different values are not grouped",
+			updates: map[int64]Data{1: {"list_price": 1.0}, 2: {"list_price": 2.0}},
+			want:    [][]int64{{1}, {2}},
+		},
+		{
+			name:    "values that cannot be encoded are written alone",
+			updates: map[int64]Data{1: {"x": make(chan int)}, 2: {"active": false}, 3: {"active": false}},
+			want:    [][]int64{{1}, {2, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := groupWrites(tt.updates)
+			got := make([][]int64, len(groups))
+			for i, g := range groups {
+				got[i] = g.ids
+				for _, id := range g.ids {
+					if !reflect.DeepEqual(g.data, tt.updates[id]) {
+						t.Errorf("group %v data = %v, want %v for id %d", g.ids, g.data, tt.updates[id], id)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupWrites() ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
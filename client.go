@@ -8,9 +8,10 @@ import (
 	"log" // Kept for defaultLogger fallback, if needed, but not for direct use
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/kolo/xmlrpc"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore" // Added for defaultLogger customization example
 )
@@ -28,17 +29,39 @@ const (
 // OdooClient represents the Odoo XML-RPC client.
 // It holds all connection parameters and session state.
 type OdooClient struct {
-	url           string
-	db            string
-	username      string
-	password      string
-	uid           int64
-	rpcClient     *xmlrpc.Client
-	lastAuth      time.Time
-	authTimeout   time.Duration
-	skipTLSVerify bool
-	httpClient    *http.Client
-	logger        *zap.Logger
+	url            string
+	db             string
+	username       string
+	credentials    CredentialProvider // yields the password, API key or bearer token, depending on credentialType
+	credentialType CredentialType
+	transport      Transport
+	knownUID       int64    // uid supplied with WithUID; skips common.authenticate
+	sess           *session // shared with the clients derived through WithContext
+	authTimeout    time.Duration
+	callTimeout    time.Duration // deadline of each call, see WithTimeout
+	dbTimeout      time.Duration // deadline of DBService operations, see WithDBTimeout
+	skipTLSVerify  bool
+	httpClient     *http.Client
+	logger         *zap.Logger
+	defaultContext OdooContext // merged into the `context` kwarg of every call
+	batchSize      int         // max ids per call of batched operations, see WithBatchSize
+	concurrency    int         // max calls in flight of batched operations, see WithConcurrency
+}
+
+// session holds the authentication state of an OdooClient. Derived clients (see
+// OdooClient.WithContext) point to the same session, so they share one login.
+type session struct {
+	mu       sync.Mutex
+	uid      int64
+	caller   rpcCaller
+	lastAuth time.Time
+	version  *ServerVersion     // cached by ServerVersion, reset on re-authentication
+	schemas  map[string]*Schema // cached by Fields, keyed by model and language
+
+	currencies map[int64]*Currency // cached by Currencies
+	precisions map[string]int      // cached by DecimalPrecision
+
+	web *OdooClient // web session opened by webSession for the other transports
 }
 
 // createLogger crea una instancia de Zap logger basada en el entorno especificado.
@@ -80,6 +103,25 @@ func WithAuthTimeout(d time.Duration) Option {
 	}
 }
 
+// WithTimeout establece el tiempo máximo de cada llamada a Odoo, incluida la autenticación,
+// WebRequest y CallRoute. Se aplica como fecha límite del contexto de la llamada, por lo que
+// funciona con todos los transportes (XML-RPC incluido); un contexto con una fecha límite
+// anterior la mantiene. En WebRequest cubre también la lectura del cuerpo de la respuesta,
+// como http.Client.Timeout. DBService tiene su propio límite (ver WithDBTimeout).
+func WithTimeout(d time.Duration) Option {
+	return func(c *OdooClient) {
+		c.callTimeout = d
+	}
+}
+
+// callContext bounds ctx by the timeout set with WithTimeout, if any.
+func (c *OdooClient) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.callTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.callTimeout)
+}
+
 // WithSkipTLSVerify establece si se debe omitir la verificación de certificados TLS.
 // ADVERTENCIA: No usar en producción.
 func WithSkipTLSVerify(skip bool) Option {
@@ -111,6 +153,38 @@ func WithLoggerEnv(env LoggerEnv) Option {
 	}
 }
 
+// WithDefaultContext establece el contexto de Odoo (lang, tz, allowed_company_ids,
+// active_test...) que se fusiona en los kwargs de cada llamada. Las claves presentes en
+// Options.Context de una llamada concreta tienen prioridad, clave por clave.
+func WithDefaultContext(odooCtx OdooContext) Option {
+	return func(c *OdooClient) {
+		c.defaultContext = odooCtx.clone()
+	}
+}
+
+// WithContext returns a derived client whose default Odoo context is the receiver's merged
+// with odooCtx (entries in odooCtx win). The derived client shares the receiver's session
+// and configuration, so no extra authentication takes place:
+//
+//	es := client.WithContext(godoo.OdooContext{"lang": "es_VE"})
+//	names, err := es.Read(ctx, godoo.ModelProductTemplate, ids, godoo.Fields{"name"})
+func (c *OdooClient) WithContext(odooCtx OdooContext) *OdooClient {
+	derived := *c
+	derived.defaultContext = c.defaultContext.clone()
+	if derived.defaultContext == nil && len(odooCtx) > 0 {
+		derived.defaultContext = make(OdooContext, len(odooCtx))
+	}
+	for k, v := range odooCtx {
+		derived.defaultContext[k] = v
+	}
+	return &derived
+}
+
+// DefaultContext returns a copy of the Odoo context merged into every call made by the client.
+func (c *OdooClient) DefaultContext() OdooContext {
+	return c.defaultContext.clone()
+}
+
 // New creates a new OdooClient instance with functional options.
 func New(urlStr, db, username, password string, opts ...Option) (*OdooClient, error) {
 	parsedURL, err := url.Parse(urlStr)
@@ -122,19 +196,30 @@ func New(urlStr, db, username, password string, opts ...Option) (*OdooClient, er
 	}
 
 	client := &OdooClient{
-		url:         urlStr,
+		url:         strings.TrimRight(urlStr, "/"), // Routes are appended as "/web/...", "/xmlrpc/2/..."
 		db:          db,
 		username:    username,
-		password:    password,
+		credentials: StaticCredential(password),
+		sess:        &session{},
 		authTimeout: 6 * time.Hour,
 		httpClient:  http.DefaultClient,
 		logger:      createLogger(EnvProduction),
+		batchSize:   DefaultBatchSize,
+		concurrency: DefaultConcurrency,
 	}
 
 	// Aplicar opciones
 	for _, opt := range opts {
 		opt(client)
 	}
+	if err := client.validateCredentials(); err != nil {
+		return nil, err
+	}
+	if client.transport == TransportWebSession {
+		if err := client.ensureCookieJar(); err != nil {
+			return nil, err
+		}
+	}
 
 	// Aplicar skipTLSVerify al Transport del httpClient
 	if client.skipTLSVerify {
@@ -142,12 +227,24 @@ func New(urlStr, db, username, password string, opts ...Option) (*OdooClient, er
 			zap.String("component", "OdooClient"),
 			zap.String("action", "New"),
 		)
+		base, ok := client.httpClient.Transport.(*http.Transport)
 		if client.httpClient.Transport == nil {
-			client.httpClient.Transport = &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			base, ok = http.DefaultTransport.(*http.Transport), true
+		}
+		if ok {
+			// The insecure setting gets a transport of its own: the configured one may be
+			// shared (http.DefaultTransport, a Manager's per-host transport) with clients
+			// that verify certificates.
+			if base.TLSClientConfig == nil || !base.TLSClientConfig.InsecureSkipVerify {
+				tr := base.Clone()
+				if tr.TLSClientConfig == nil {
+					tr.TLSClientConfig = &tls.Config{}
+				}
+				tr.TLSClientConfig.InsecureSkipVerify = true
+				hc := *client.httpClient
+				hc.Transport = tr
+				client.httpClient = &hc
 			}
-		} else if tr, ok := client.httpClient.Transport.(*http.Transport); ok {
-			tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 		} else {
 			client.logger.Warn("Cannot apply skipTLSVerify to a custom HTTP client's non-http.Transport. Manual configuration might be needed.",
 				zap.String("component", "OdooClient"),
@@ -164,6 +261,9 @@ func New(urlStr, db, username, password string, opts ...Option) (*OdooClient, er
 // It is called internally by getConnection if the authentication is invalid.
 // It now accepts a context.Context to allow for cancellation or timeouts.
 func (c *OdooClient) authenticate(ctx context.Context) error {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
 	// Check for context cancellation before starting the authentication process.
 	select {
 	case <-ctx.Done():
@@ -176,6 +276,43 @@ func (c *OdooClient) authenticate(ctx context.Context) error {
 		// Context is not done, proceed.
 	}
 
+	// The secret is fetched on every (re)authentication so rotated credentials are picked up.
+	secret, err := c.secret(ctx)
+	if err != nil {
+		c.logger.Error("Failed to obtain Odoo credential",
+			zap.Error(err),
+			zap.String("credential", string(c.credentialType)),
+			zap.String("op", "authenticate"),
+		)
+		return err
+	}
+
+	switch c.transport {
+	case TransportWebSession:
+		return c.authenticateWeb(ctx, secret)
+	case TransportJSONRPC:
+		return c.authenticateJSONRPC(ctx, secret)
+	}
+
+	if c.transport == TransportJSON2 {
+		// The JSON-2 API is stateless: every request carries the bearer key,
+		// so there is no login round trip to perform here.
+		c.sess.uid = c.knownUID
+		c.sess.caller = &json2Caller{
+			httpClient: c.httpClient,
+			baseURL:    c.url,
+			db:         c.db,
+			token:      secret,
+		}
+		c.sess.lastAuth = time.Now()
+		c.logger.Info("Configured Odoo JSON-2 transport",
+			zap.String("db", c.db),
+			zap.String("credential", string(c.credentialType)),
+			zap.String("op", "authenticate"),
+		)
+		return nil
+	}
+
 	// The xmlrpc.NewClient from 'kolo' expects a *http.Transport.
 	// We need to extract it from the OdooClient's httpClient.
 	var tr *http.Transport
@@ -226,28 +363,48 @@ func (c *OdooClient) authenticate(ctx context.Context) error {
 	// If the context has a deadline, we could potentially set `commonRPCClient.SetTimeout(...)`
 	// if `kolo/xmlrpc` supported it, but it doesn't.
 
-	commonRPCClient, err := xmlrpc.NewClient(commonURL, tr)
-	if err != nil {
-		c.logger.Error("Failed to connect to Odoo common endpoint during authentication",
-			zap.Error(err),
-			zap.String("url", commonURL),
-			zap.String("op", "authenticate"),
-		)
-		return fmt.Errorf("failed to connect to Odoo common endpoint: %w", err)
-	}
-	defer commonRPCClient.Close() // Close the common client after use
+	// When the uid is already known (WithUID) the common.authenticate round trip is skipped:
+	// execute_kw re-validates the password or API key on every call anyway.
+	uid := c.knownUID
+	if uid == 0 {
+		commonRPCClient, err := newXMLRPCClient(commonURL, tr)
+		if err != nil {
+			c.logger.Error("Failed to connect to Odoo common endpoint during authentication",
+				zap.Error(err),
+				zap.String("url", commonURL),
+				zap.String("op", "authenticate"),
+			)
+			return fmt.Errorf("failed to connect to Odoo common endpoint: %w", err)
+		}
 
-	var uid int64
-	err = commonRPCClient.Call("authenticate", []interface{}{c.db, c.username, c.password, map[string]interface{}{}}, &uid)
-	if err != nil {
-		c.logger.Error("Odoo authentication failed",
-			zap.Error(err),
-			zap.String("db", c.db),
-			zap.String("username", c.username),
-			zap.String("op", "authenticate"),
-		)
-		// Consider using the specific error types defined in godoo/errors.go
-		return fmt.Errorf("%w: %s", ErrAuthenticationFailed, err.Error())
+		// Odoo answers `false` (not a fault) for wrong credentials, so decode into
+		// an interface{} and only accept a positive integer uid.
+		var result interface{}
+		err = callXMLRPC(ctx, commonRPCClient, "authenticate", []interface{}{c.db, c.username, secret, map[string]interface{}{}}, &result, closeXMLRPC(commonRPCClient))
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.logger.Error("Odoo authentication failed",
+				zap.Error(err),
+				zap.String("db", c.db),
+				zap.String("username", c.username),
+				zap.String("op", "authenticate"),
+			)
+			// Consider using the specific error types defined in godoo/errors.go
+			return fmt.Errorf("%w: %s", ErrAuthenticationFailed, err.Error())
+		}
+		if id, ok := result.(int64); ok && id > 0 {
+			uid = id
+		} else {
+			c.logger.Error("Odoo rejected the credentials",
+				zap.String("db", c.db),
+				zap.String("username", c.username),
+				zap.String("credential", string(c.credentialType)),
+				zap.String("op", "authenticate"),
+			)
+			return fmt.Errorf("%w: invalid %s for user '%s' on database '%s'", ErrAuthenticationFailed, c.credentialType, c.username, c.db)
+		}
 	}
 
 	// Check for context cancellation after the first RPC call (authenticate) but before the next.
@@ -263,7 +420,7 @@ func (c *OdooClient) authenticate(ctx context.Context) error {
 	}
 
 	objectURL := fmt.Sprintf("%s/xmlrpc/2/object", c.url)
-	objectRPCClient, err := xmlrpc.NewClient(objectURL, tr)
+	objectRPCClient, err := newXMLRPCClient(objectURL, tr)
 	if err != nil {
 		c.logger.Error("Failed to connect to Odoo object endpoint after authentication",
 			zap.Error(err),
@@ -274,11 +431,12 @@ func (c *OdooClient) authenticate(ctx context.Context) error {
 	}
 	// Do not close objectRPCClient here, as it's stored and reused
 
-	c.uid = uid
-	c.rpcClient = objectRPCClient // Store the client for later use.
-	c.lastAuth = time.Now()
+	c.sess.uid = uid
+	// Store the client for later use.
+	c.sess.caller = &xmlrpcCaller{client: objectRPCClient, db: c.db, uid: uid, secret: secret}
+	c.sess.lastAuth = time.Now()
 	c.logger.Info("Successfully authenticated with Odoo",
-		zap.Int64("uid", c.uid),
+		zap.Int64("uid", c.sess.uid),
 		zap.String("db", c.db),
 		zap.String("op", "authenticate"),
 	)
@@ -287,12 +445,59 @@ func (c *OdooClient) authenticate(ctx context.Context) error {
 
 // isAuthValid checks if the current authentication is valid (not expired and client exists).
 func (c *OdooClient) isAuthValid() bool {
-	return c.uid != 0 && c.rpcClient != nil && time.Since(c.lastAuth) < c.authTimeout
+	return c.sess.caller != nil && time.Since(c.sess.lastAuth) < c.authTimeout
 }
 
-// getConnection returns the user ID and the RPC client, authenticating if necessary.
+// invalidate drops the current session, and the schemas cached with it, so the next
+// getConnection re-authenticates.
+func (c *OdooClient) invalidate() {
+	c.sess.mu.Lock()
+	defer c.sess.mu.Unlock()
+	if c.sess.caller != nil {
+		c.sess.caller.close()
+		c.sess.caller = nil
+	}
+	c.sess.schemas = nil
+}
+
+// invalidateCaller drops the session only if it still uses caller, the one a call just
+// failed with. When concurrent calls hit an expired session, the first one re-authenticates
+// and the others reuse its new session instead of tearing it down again.
+func (c *OdooClient) invalidateCaller(caller rpcCaller) {
+	c.sess.mu.Lock()
+	defer c.sess.mu.Unlock()
+	if c.sess.caller != nil && c.sess.caller == caller {
+		c.sess.caller.close()
+		c.sess.caller = nil
+	}
+}
+
+// Close drops the client's session and releases idle connections. Clients derived with
+// WithContext share the session, so they are closed too. A closed client re-authenticates
+// transparently if it is used again.
+func (c *OdooClient) Close() {
+	c.invalidate()
+	c.sess.mu.Lock()
+	web := c.sess.web
+	c.sess.web = nil
+	c.sess.mu.Unlock()
+	if web != nil {
+		web.invalidate()
+	}
+	c.httpClient.CloseIdleConnections()
+}
+
+// Ping checks that the server is reachable and the credentials are accepted by
+// authenticating if needed and performing a trivial indexed query.
+func (c *OdooClient) Ping(ctx context.Context) error {
+	var count int64
+	return c.executeRPC(ctx, string(ModelResUsers), "search_count", []interface{}{Domain{{"id", "=", 0}}.ToRPC()}, nil, &count)
+}
+
+// getConnection returns the user ID and the RPC caller, authenticating if necessary.
 // It now accepts a context.Context to allow for cancellation or timeouts during connection.
-func (c *OdooClient) getConnection(ctx context.Context) (int64, *xmlrpc.Client, error) {
+// It is safe for concurrent use: the session state is guarded by c.sess.mu.
+func (c *OdooClient) getConnection(ctx context.Context) (int64, rpcCaller, error) {
 	// Check for context cancellation before proceeding
 	select {
 	case <-ctx.Done():
@@ -302,15 +507,29 @@ func (c *OdooClient) getConnection(ctx context.Context) (int64, *xmlrpc.Client,
 		// Continue
 	}
 
+	c.sess.mu.Lock()
+	authenticated := false
 	if !c.isAuthValid() {
-		if c.rpcClient != nil {
-			c.rpcClient.Close()
-			c.rpcClient = nil
+		if c.sess.caller != nil {
+			c.sess.caller.close()
+			c.sess.caller = nil
 		}
+		// The server may have been upgraded while the session was down.
+		c.sess.version = nil
+		c.sess.schemas = nil
 		// Pass the context to the authentication process
 		if err := c.authenticate(ctx); err != nil {
+			c.sess.mu.Unlock()
 			return 0, nil, err
 		}
+		authenticated = true
+	}
+	uid, caller := c.sess.uid, c.sess.caller
+	c.sess.mu.Unlock()
+
+	// The version is fetched without the lock, so other calls need not wait for it.
+	if authenticated {
+		c.cacheServerVersion(ctx, caller)
 	}
-	return c.uid, c.rpcClient, nil
+	return uid, caller, nil
 }
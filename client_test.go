@@ -0,0 +1,124 @@
+// godoo/client_test.go
+package godoo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithTimeoutBoundsEveryCall(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}
+	srv.mux.HandleFunc("/custom/slow", slow)
+	srv.mux.HandleFunc("/custom/slow.json", slow)
+	srv.handle = func(call fakeCall) (interface{}, error) {
+		<-release
+		return true, nil
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		call func(c *OdooClient) error
+	}{
+		{"xmlrpc execute_kw", nil, func(c *OdooClient) error {
+			_, err := c.Search(context.Background(), ModelResPartner, nil)
+			return err
+		}},
+		{"jsonrpc execute_kw", []Option{WithTransport(TransportJSONRPC)}, func(c *OdooClient) error {
+			_, err := c.Search(context.Background(), ModelResPartner, nil)
+			return err
+		}},
+		{"WebRequest", []Option{WithTransport(TransportJSONRPC)}, func(c *OdooClient) error {
+			resp, err := c.WebRequest(context.Background(), http.MethodGet, "/custom/slow", nil, nil)
+			if err == nil {
+				resp.Body.Close()
+			}
+			return err
+		}},
+		{"CallRoute", []Option{WithTransport(TransportJSONRPC)}, func(c *OdooClient) error {
+			return c.CallRoute(context.Background(), "/custom/slow.json", nil, nil)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := srv.client(t, append([]Option{WithTimeout(50 * time.Millisecond)}, tt.opts...)...)
+			start := time.Now()
+			err := tt.call(c)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("error = %v, want context.DeadlineExceeded", err)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("call returned after %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestWebRequestTimeoutCoversBody(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.mux.HandleFunc("/custom/trickle", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "first")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	c := srv.client(t, WithTransport(TransportJSONRPC), WithTimeout(50*time.Millisecond))
+
+	resp, err := c.WebRequest(context.Background(), http.MethodGet, "/custom/trickle", nil, nil)
+	if err != nil {
+		t.Fatalf("WebRequest: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(string(raw), "first") || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("body = %q, error = %v; want the first chunk and context.DeadlineExceeded", raw, err)
+	}
+}
+
+func TestDBServiceTimeouts(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.db = func(method string, args []interface{}) (interface{}, error) {
+		time.Sleep(200 * time.Millisecond)
+		return []string{"test"}, nil
+	}
+	srv.mux.HandleFunc("/web/database/backup", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			io.WriteString(w, "backup")
+		case <-r.Context().Done():
+		}
+	})
+	ctx := context.Background()
+
+	// The call timeout bounds the db service calls, but not dumps.
+	c := srv.client(t, WithTimeout(50*time.Millisecond))
+	if _, err := c.DBService(StaticCredential("master")).List(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("List error = %v, want context.DeadlineExceeded", err)
+	}
+	var buf strings.Builder
+	if _, err := c.DBService(StaticCredential("master")).Dump(ctx, "test", DumpZip, &buf); err != nil || buf.String() != "backup" {
+		t.Errorf("Dump = %q, %v; want the backup", buf.String(), err)
+	}
+
+	// WithDBTimeout bounds both, and replaces the call timeout.
+	c = srv.client(t, WithTimeout(50*time.Millisecond), WithDBTimeout(time.Second))
+	if names, err := c.DBService(StaticCredential("master")).List(ctx); err != nil || len(names) != 1 {
+		t.Errorf("List = %v, %v; want [test]", names, err)
+	}
+	c = srv.client(t, WithDBTimeout(50*time.Millisecond))
+	if _, err := c.DBService(StaticCredential("master")).Dump(ctx, "test", DumpZip, io.Discard); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Dump error = %v, want context.DeadlineExceeded", err)
+	}
+}
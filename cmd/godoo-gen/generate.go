@@ -0,0 +1,274 @@
+// godoo/cmd/godoo-gen/generate.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ilcreatore32/godoo"
+)
+
+// initialisms are rendered in upper case in Go identifiers (partner_id -> PartnerID).
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "CSS": true, "CSV": true, "DB": true, "EAN": true, "HTML": true,
+	"HTTP": true, "ID": true, "IDS": true, "IP": true, "JSON": true, "PDF": true, "SKU": true,
+	"SMS": true, "SQL": true, "TZ": true, "UID": true, "UOM": true, "URL": true, "UUID": true,
+	"VAT": true, "XML": true,
+}
+
+// goName converts an Odoo identifier (sale.order, partner_id, x_studio_color) to an exported
+// Go identifier (SaleOrder, PartnerID, XStudioColor).
+func goName(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		upper := strings.ToUpper(word)
+		switch {
+		case upper == "IDS":
+			b.WriteString("IDs")
+		case initialisms[upper]:
+			b.WriteString(upper)
+		default:
+			runes := []rune(strings.ToLower(word))
+			runes[0] = unicode.ToUpper(runes[0])
+			b.WriteString(string(runes))
+		}
+	}
+	name := b.String()
+	if name == "" {
+		return "X"
+	}
+	if unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
+
+// uniqueNames hands out identifiers, suffixing them when two Odoo names collide in Go.
+type uniqueNames map[string]bool
+
+func (u uniqueNames) take(name string) string {
+	candidate := name
+	for i := 2; u[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	u[candidate] = true
+	return candidate
+}
+
+// goType returns the Go type of a field; selection fields use their generated enum type.
+func goType(f *godoo.FieldSchema, enum string) string {
+	switch f.Type {
+	case godoo.FieldChar, godoo.FieldText, godoo.FieldHTML, godoo.FieldBinary, godoo.FieldReference:
+		return "string"
+	case godoo.FieldSelection:
+		if enum != "" {
+			return enum
+		}
+		return "string"
+	case godoo.FieldInteger, godoo.FieldMany2oneReference:
+		return "int64"
+	case godoo.FieldFloat, godoo.FieldMonetary:
+		return "float64"
+	case godoo.FieldBoolean:
+		return "bool"
+	case godoo.FieldDate:
+		return "godoo.Date"
+	case godoo.FieldDatetime:
+		return "godoo.DateTime"
+	case godoo.FieldMany2one:
+		if f.Relation != "" {
+			return refType(f.Relation)
+		}
+		return "godoo.Many2One"
+	case godoo.FieldOne2many, godoo.FieldMany2many:
+		if f.Relation != "" {
+			return idsType(f.Relation)
+		}
+		return "[]int64"
+	default:
+		return "interface{}"
+	}
+}
+
+// refType is the generated many2one type of the records of model (res.partner -> ResPartnerRef).
+func refType(model godoo.Model) string {
+	return goName(string(model)) + "Ref"
+}
+
+// idsType is the generated x2many type of the records of model (res.partner -> ResPartnerIDs).
+func idsType(model godoo.Model) string {
+	return goName(string(model)) + "IDs"
+}
+
+// comment flattens a label or help text into a single line.
+func comment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// generate renders the Go source of one model: the model constant, the record struct,
+// selection enums and field-name constants.
+func generate(pkg string, schema *godoo.Schema, modelLabel string) ([]byte, error) {
+	typeName := goName(string(schema.Model))
+	// Field names sorted, with the id first.
+	names := []string{}
+	if schema.Field("id") != nil {
+		names = append(names, "id")
+	}
+	for _, name := range schema.Names() {
+		if name != "id" {
+			names = append(names, name)
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by godoo-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/ilcreatore32/godoo\"\n\n")
+
+	fmt.Fprintf(&b, "// Model%s is the technical name of the %s model.\n", typeName, schema.Model)
+	fmt.Fprintf(&b, "const Model%s godoo.Model = %q\n\n", typeName, schema.Model)
+
+	// Selection enums first, so the struct can refer to them.
+	enums := map[string]string{}
+	for _, name := range names {
+		f := schema.Fields[name]
+		if f.Type != godoo.FieldSelection || len(f.Selection) == 0 {
+			continue
+		}
+		enum := typeName + goName(name)
+		if enum == refType(schema.Model) || enum == idsType(schema.Model) {
+			// A selection named ref or ids would clash with the relation types.
+			enum += "Selection"
+		}
+		enums[name] = enum
+		fmt.Fprintf(&b, "// %s is a value of %s.%s", enum, schema.Model, name)
+		if f.String != "" {
+			fmt.Fprintf(&b, " (%s)", comment(f.String))
+		}
+		fmt.Fprintf(&b, ".\ntype %s string\n\n", enum)
+		fmt.Fprintf(&b, "// Values of %s.\nconst (\n", enum)
+		values := uniqueNames{}
+		for _, opt := range f.Selection {
+			ident := values.take(enum + goName(opt.Value))
+			fmt.Fprintf(&b, "\t%s %s = %q", ident, enum, opt.Value)
+			if opt.Label != "" {
+				fmt.Fprintf(&b, " // %s", comment(opt.Label))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(")\n\n")
+	}
+
+	fields := uniqueNames{}
+	goFields := make(map[string]string, len(names))
+	for _, name := range names {
+		goFields[name] = fields.take(goName(name))
+	}
+
+	fmt.Fprintf(&b, "// %s is a record of %s", typeName, schema.Model)
+	if modelLabel != "" {
+		fmt.Fprintf(&b, " (%s)", comment(modelLabel))
+	}
+	fmt.Fprintf(&b, ".\ntype %s struct {\n", typeName)
+	for _, name := range names {
+		f := schema.Fields[name]
+		tag := name
+		if f.Readonly && name != "id" {
+			tag += ",readonly"
+		}
+		fmt.Fprintf(&b, "\t%s %s `odoo:%q`", goFields[name], goType(f, enums[name]), tag)
+		var notes []string
+		if f.String != "" {
+			notes = append(notes, comment(f.String))
+		}
+		if f.Relation != "" {
+			notes = append(notes, "-> "+string(f.Relation))
+		}
+		if f.Required {
+			notes = append(notes, "required")
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, " // %s", strings.Join(notes, ", "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "// Field names of %s.\nconst (\n", schema.Model)
+	for _, name := range names {
+		fmt.Fprintf(&b, "\t%sField%s = %q\n", typeName, goFields[name], name)
+	}
+	b.WriteString(")\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code for %s: %w", schema.Model, err)
+	}
+	return src, nil
+}
+
+// relationsFile is the generated file declaring the relation types.
+const relationsFile = "relations.go"
+
+// generateRelations renders the relation types of every generated model and of every model
+// their relational fields point to: a many2one type embedding godoo.Many2One and an x2many
+// type of ids, so that a field holding partners cannot be assigned one holding products.
+func generateRelations(pkg string, schemas map[godoo.Model]*godoo.Schema) ([]byte, error) {
+	targets := map[godoo.Model]bool{}
+	for model, schema := range schemas {
+		targets[model] = true
+		for _, f := range schema.Fields {
+			if f.Relation != "" && (f.Type == godoo.FieldMany2one || f.Type == godoo.FieldOne2many || f.Type == godoo.FieldMany2many) {
+				targets[f.Relation] = true
+			}
+		}
+	}
+	models := make([]godoo.Model, 0, len(targets))
+	for m := range targets {
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i] < models[j] })
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by godoo-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/ilcreatore32/godoo\"\n\n")
+	for _, model := range models {
+		ref, ids := refType(model), idsType(model)
+		fmt.Fprintf(&b, "// %s is a many2one reference to a %s record.\n", ref, model)
+		fmt.Fprintf(&b, "type %s struct {\n\tgodoo.Many2One\n}\n\n", ref)
+		fmt.Fprintf(&b, "// Model returns the model of the referenced record.\n")
+		fmt.Fprintf(&b, "func (%s) Model() godoo.Model { return %q }\n\n", ref, model)
+		fmt.Fprintf(&b, "// %s are the ids of the %s records of an x2many field.\n", ids, model)
+		fmt.Fprintf(&b, "type %s []int64\n\n", ids)
+		fmt.Fprintf(&b, "// Model returns the model of the records.\n")
+		fmt.Fprintf(&b, "func (%s) Model() godoo.Model { return %q }\n\n", ids, model)
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated relation types: %w", err)
+	}
+	return src, nil
+}
+
+// fileName returns the generated file name of a model (sale.order -> sale_order.go).
+func fileName(model godoo.Model) string {
+	return strings.ReplaceAll(string(model), ".", "_") + ".go"
+}
+
+// sortedModels returns the keys of a schema set in a stable order.
+func sortedModels(schemas map[godoo.Model]*godoo.Schema) []godoo.Model {
+	models := make([]godoo.Model, 0, len(schemas))
+	for m := range schemas {
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i] < models[j] })
+	return models
+}
@@ -0,0 +1,72 @@
+// godoo/cmd/godoo-gen/generate_test.go
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the generated code")
+
+// TestGenerateGolden generates the models of testdata/fields.json and compares every file
+// with testdata/golden/<file>.golden. Run with -update after an intended change.
+func TestGenerateGolden(t *testing.T) {
+	out := t.TempDir()
+	if err := run(filepath.Join("testdata", "fields.json"), "", "", "", "", "", out, "odoomodels", 0); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	files, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"relations.go", "res_partner.go", "sale_order.go"}
+	if len(files) != len(want) {
+		t.Fatalf("generated %d files, want %v", len(files), want)
+	}
+	for i, f := range files {
+		if f.Name() != want[i] {
+			t.Fatalf("generated %s, want %s", f.Name(), want[i])
+		}
+		got, err := os.ReadFile(filepath.Join(out, f.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		golden := filepath.Join("testdata", "golden", f.Name()+".golden")
+		if *update {
+			if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(golden, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("%v (run go test -update to create it)", err)
+		}
+		if string(got) != string(expected) {
+			t.Errorf("%s differs from %s:\n%s", f.Name(), golden, got)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"sale.order":     "SaleOrder",
+		"partner_id":     "PartnerID",
+		"tag_ids":        "TagIDs",
+		"x_studio_color": "XStudioColor",
+		"l10n_ve_vat":    "L10nVeVAT",
+		"2fa_enabled":    "X2faEnabled",
+		"__last_update":  "LastUpdate",
+	}
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
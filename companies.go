@@ -0,0 +1,159 @@
+// godoo/companies.go
+package godoo
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// CompanyInfo identifies an Odoo company (res.company) the current user can access.
+type CompanyInfo struct {
+	ID   int64
+	Name string
+}
+
+// UserCompanies describes the companies of the authenticated user, as stored on res.users.
+type UserCompanies struct {
+	Current CompanyInfo   // The user's default company (company_id)
+	Allowed []CompanyInfo // Every company the user may access (company_ids), current first
+}
+
+// IDs returns the ids of the allowed companies, current company first.
+func (u *UserCompanies) IDs() []int64 {
+	ids := make([]int64, len(u.Allowed))
+	for i, company := range u.Allowed {
+		ids[i] = company.ID
+	}
+	return ids
+}
+
+// currentUID returns the uid of the authenticated user. Transports that do not need a uid
+// (JSON-2 without WithUID) resolve it once from res.users by login.
+func (c *OdooClient) currentUID(ctx context.Context) (int64, error) {
+	uid, _, err := c.getConnection(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if uid != 0 {
+		return uid, nil
+	}
+
+	uid, err = c.SearchOne(ctx, ModelResUsers, Domain{{"login", "=", c.username}})
+	if err != nil {
+		return 0, fmt.Errorf("godoo: cannot determine uid of user '%s': %w", c.username, err)
+	}
+	c.sess.mu.Lock()
+	if c.sess.uid == 0 {
+		c.sess.uid = uid
+	}
+	c.sess.mu.Unlock()
+	return uid, nil
+}
+
+// AllowedCompanies reads the authenticated user's default company and the companies they
+// are allowed to access from res.users. The result is not cached, so changes to the user's
+// access rights are seen immediately.
+func (c *OdooClient) AllowedCompanies(ctx context.Context) (*UserCompanies, error) {
+	c.logger.Debug("Reading Odoo user companies", zap.String("op", "AllowedCompanies"))
+
+	uid, err := c.currentUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// active_test=false: an archived company may still be the user's default.
+	user, err := c.ReadOne(ctx, ModelResUsers, uid, Fields{"company_id", "company_ids"}, ActiveTest(false))
+	if err != nil {
+		return nil, err
+	}
+
+	current, _ := many2oneID(user["company_id"])
+	ids, err := toIDs(user["company_ids"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: res.users.company_ids: %v", ErrInvalidResponse, err)
+	}
+	// Keep the default company first so it becomes the active one when passed on.
+	ordered := []int64{}
+	if current != 0 {
+		ordered = append(ordered, current)
+	}
+	for _, id := range ids {
+		if id != current {
+			ordered = append(ordered, id)
+		}
+	}
+
+	names := map[int64]string{}
+	if len(ordered) > 0 {
+		records, err := c.Read(ctx, ModelResCompany, ordered, Fields{"name"}, Company(ordered...))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			id, _ := toID(r["id"])
+			name, _ := r["name"].(string)
+			names[id] = name
+		}
+	}
+
+	result := &UserCompanies{Current: CompanyInfo{ID: current, Name: names[current]}}
+	for _, id := range ordered {
+		result.Allowed = append(result.Allowed, CompanyInfo{ID: id, Name: names[id]})
+	}
+
+	c.logger.Info("Odoo user companies read",
+		zap.Int64("uid", uid),
+		zap.Int64("current_company", current),
+		zap.Int("allowed_count", len(result.Allowed)),
+		zap.String("op", "AllowedCompanies"),
+	)
+	return result, nil
+}
+
+// ForCompany returns a derived client (sharing the session, see WithContext) whose calls
+// run in `companyID`: its context sets `allowed_company_ids` to companyID followed by
+// `alsoAllowed`, and `force_company` for Odoo versions before 13. When the server version
+// is already known (see ServerVersion) force_company is only sent to servers that use it.
+func (c *OdooClient) ForCompany(companyID int64, alsoAllowed ...int64) *OdooClient {
+	allowed := []int64{companyID}
+	for _, id := range alsoAllowed {
+		if id != companyID {
+			allowed = append(allowed, id)
+		}
+	}
+	ctx := OdooContext{"allowed_company_ids": allowed}
+
+	c.sess.mu.Lock()
+	version := c.sess.version
+	c.sess.mu.Unlock()
+	if version == nil || !version.Supports(FeatureAllowedCompanyIDs) {
+		ctx["force_company"] = companyID
+	}
+	return c.WithContext(ctx)
+}
+
+// ForEachCompany runs fn once per company the user is allowed to access, in turn, passing
+// a client bound to that company (see ForCompany). Iteration stops at the first error,
+// which is returned annotated with the company, or when ctx is done.
+func (c *OdooClient) ForEachCompany(ctx context.Context, fn func(ctx context.Context, client *OdooClient, company CompanyInfo) error) error {
+	companies, err := c.AllowedCompanies(ctx)
+	if err != nil {
+		return err
+	}
+	for _, company := range companies.Allowed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		c.logger.Debug("Running function for Odoo company",
+			zap.Int64("company_id", company.ID),
+			zap.String("company", company.Name),
+			zap.String("op", "ForEachCompany"),
+		)
+		if err := fn(ctx, c.ForCompany(company.ID), company); err != nil {
+			return fmt.Errorf("company %d (%s): %w", company.ID, company.Name, err)
+		}
+	}
+	return nil
+}
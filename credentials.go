@@ -0,0 +1,134 @@
+// godoo/credentials.go
+package godoo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrMissingCredential indica que un CredentialProvider no pudo entregar el secreto.
+var ErrMissingCredential = errors.New("godoo: credential not available")
+
+// CredentialProvider supplies the secret (password, API key or bearer token) used to
+// authenticate with Odoo. The client consults it on every (re)authentication instead of
+// keeping a fixed string, so a rotated secret is picked up without recreating the client.
+//
+// Implementations must be safe for concurrent use.
+type CredentialProvider interface {
+	Secret(ctx context.Context) (string, error)
+}
+
+// CredentialFunc adapts a function (e.g. a Vault or cloud secret manager lookup)
+// to the CredentialProvider interface.
+type CredentialFunc func(ctx context.Context) (string, error)
+
+// Secret calls f(ctx).
+func (f CredentialFunc) Secret(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// staticCredential always returns the same secret. It backs the password passed to New.
+type staticCredential string
+
+func (s staticCredential) Secret(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// StaticCredential returns a CredentialProvider for a fixed secret.
+func StaticCredential(secret string) CredentialProvider {
+	return staticCredential(secret)
+}
+
+// envCredential reads the secret from an environment variable on every call.
+type envCredential string
+
+func (e envCredential) Secret(context.Context) (string, error) {
+	v, ok := os.LookupEnv(string(e))
+	if !ok || v == "" {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrMissingCredential, string(e))
+	}
+	return v, nil
+}
+
+// EnvCredential returns a CredentialProvider that reads the secret from the
+// environment variable `name` each time the client authenticates.
+func EnvCredential(name string) CredentialProvider {
+	return envCredential(name)
+}
+
+// fileCredential reads the secret from a file, re-reading it only when the file changes.
+type fileCredential struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	secret  string
+}
+
+// FileCredential returns a CredentialProvider backed by the file at `path`, such as a
+// mounted Kubernetes secret. The file is stat'ed on every authentication and re-read when
+// its modification time or size changes; surrounding whitespace is trimmed.
+func FileCredential(path string) CredentialProvider {
+	return &fileCredential{path: path}
+}
+
+func (f *fileCredential) Secret(context.Context) (string, error) {
+	// os.Stat follows symlinks, so the atomic `..data` swap Kubernetes performs
+	// on secret volumes is seen as a change of the target file.
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrMissingCredential, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.secret != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.secret, nil
+	}
+
+	raw, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrMissingCredential, err)
+	}
+	secret := string(bytes.TrimSpace(raw))
+	if secret == "" {
+		return "", fmt.Errorf("%w: file %s is empty", ErrMissingCredential, f.path)
+	}
+
+	f.secret = secret
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return secret, nil
+}
+
+// WithCredentialProvider establece la fuente del secreto y su tipo (contraseña, API key o
+// bearer). El proveedor se consulta en cada (re)autenticación, por lo que la rotación del
+// secreto se aplica sin reiniciar el servicio. Anula la contraseña pasada a New.
+func WithCredentialProvider(kind CredentialType, provider CredentialProvider) Option {
+	return func(c *OdooClient) {
+		c.credentialType = kind
+		c.credentials = provider
+	}
+}
+
+// secret asks the configured CredentialProvider for the current secret.
+func (c *OdooClient) secret(ctx context.Context) (string, error) {
+	if c.credentials == nil {
+		return "", fmt.Errorf("%w: no credential provider configured", ErrMissingCredential)
+	}
+	s, err := c.credentials.Secret(ctx)
+	if err != nil {
+		return "", err
+	}
+	if s == "" {
+		return "", fmt.Errorf("%w: credential provider returned an empty secret", ErrMissingCredential)
+	}
+	return s, nil
+}
@@ -0,0 +1,83 @@
+// godoo/credentials_test.go
+package godoo
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestRotatedSecretReauthenticatesOnce(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"xmlrpc", nil},
+		{"jsonrpc", []Option{WithTransport(TransportJSONRPC)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeOdoo(t, "old")
+			srv.handle = func(call fakeCall) (interface{}, error) { return []int64{1}, nil }
+			provider := CredentialFunc(func(context.Context) (string, error) { return srv.currentSecret(), nil })
+			c := srv.client(t, append([]Option{WithCredentialProvider(CredentialAPIKey, provider)}, tt.opts...)...)
+			ctx := context.Background()
+
+			if err := c.Ping(ctx); err != nil {
+				t.Fatalf("Ping: %v", err)
+			}
+			srv.setSecret("new")
+
+			// Every call fails with the old secret at the same time: only one of them may
+			// log in again, the others must reuse the new session.
+			const n = 8
+			var wg sync.WaitGroup
+			errs := make(chan error, n)
+			for i := 0; i < n; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := c.Search(ctx, ModelResPartner, nil)
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Errorf("Search after rotation: %v", err)
+				}
+			}
+			if logins := srv.loginCount(); logins != 2 {
+				t.Errorf("logins = %d, want 2 (initial login and one re-authentication)", logins)
+			}
+		})
+	}
+}
+
+func TestConcurrentWebSessionExpiryLogsInOnce(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.handle = func(call fakeCall) (interface{}, error) { return []int64{1}, nil }
+	c := srv.client(t, WithTransport(TransportWebSession))
+	ctx := context.Background()
+
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	srv.expireSessions()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Search(ctx, ModelResPartner, nil); err != nil {
+				t.Errorf("Search after expiry: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if logins := srv.loginCount(); logins != 2 {
+		t.Errorf("logins = %d, want 2", logins)
+	}
+}
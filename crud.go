@@ -3,7 +3,6 @@ package godoo
 import (
 	"context"
 	"fmt"
-	"sync"
 
 	"go.uber.org/zap"
 )
@@ -40,9 +39,12 @@ import (
 //   - error: An error if the RPC call fails, including network issues, Odoo server errors,
 //     or context cancellation/timeout.
 func (c *OdooClient) executeRPC(ctx context.Context, model, method string, args []interface{}, options map[string]interface{}, reply interface{}) error {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
 	// Assuming `c.getConnection` manages pooled connections and returns `uid` and `rpcClient`.
 	// The `uid` and `rpcClient` are typically short-lived or come from a pool.
-	uid, rpcClient, err := c.getConnection(ctx)
+	_, caller, err := c.getConnection(ctx)
 	if err != nil {
 		c.logger.Error("Failed to get Odoo connection for RPC call",
 			zap.Error(err),
@@ -52,44 +54,43 @@ func (c *OdooClient) executeRPC(ctx context.Context, model, method string, args
 		return err
 	}
 
-	// Odoo's execute_kw expects (db, uid, password, model, method, args[], kwargs{})
-	callArgs := []interface{}{c.db, uid, c.password, model, method, args}
-
-	// Append options (kwargs) if provided, otherwise an empty map.
-	// `execute_kw` always expects a kwargs dictionary, even if empty.
-	if len(options) > 0 {
-		callArgs = append(callArgs, options)
-	} else {
-		callArgs = append(callArgs, map[string]interface{}{}) // Pass an empty dict if no options
+	// Default Odoo context entries (lang, tz, companies...) apply unless the call overrides them.
+	if options, err = mergeContext(options, c.defaultContext); err != nil {
+		return err
 	}
 
-	callChan := make(chan error, 1)
-	go func() {
-		// This goroutine executes the blocking RPC call.
-		callErr := rpcClient.Call("execute_kw", callArgs, reply)
-		callChan <- callErr
-	}()
-
-	select {
-	case <-ctx.Done():
-		c.logger.Error("Odoo RPC call cancelled by context timeout/cancellation",
-			zap.Error(ctx.Err()),
+	// The transport-specific caller (execute_kw over XML-RPC, the JSON-2 endpoints or the
+	// web session routes) performs the blocking call and abandons it when ctx is done.
+	err = caller.call(ctx, model, method, args, options, reply)
+	if needsReauthentication(err) {
+		// The server dropped the web session or rejected a (possibly rotated) secret:
+		// authenticate again with a fresh credential and retry once.
+		c.logger.Info("Odoo session expired or credential rejected, re-authenticating",
 			zap.String("model", model),
 			zap.String("method", method),
 		)
-		return ctx.Err() // Return the context's error
-	case err = <-callChan:
-		// The RPC call completed (successfully or with an error).
-		// `err` now holds the result of `rpcClient.Call`.
-		if err != nil {
-			c.logger.Error("Failed to execute Odoo RPC call",
-				zap.Error(err),
-				zap.String("model", model),
-				zap.String("method", method),
-			)
-			// Parse the error to a more specific OdooRPCError if possible.
-			return parseOdooRPCError(fmt.Errorf("failed to call Odoo method '%s' on model '%s': %w", method, model, err))
+		c.invalidateCaller(caller)
+		if _, caller, err = c.getConnection(ctx); err != nil {
+			return err
 		}
+		err = caller.call(ctx, model, method, args, options, reply)
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		c.logger.Error("Odoo RPC call cancelled by context timeout/cancellation",
+			zap.Error(ctxErr),
+			zap.String("model", model),
+			zap.String("method", method),
+		)
+		return ctxErr // Return the context's error
+	}
+	if err != nil {
+		c.logger.Error("Failed to execute Odoo RPC call",
+			zap.Error(err),
+			zap.String("model", model),
+			zap.String("method", method),
+		)
+		// Parse the error to a more specific OdooRPCError if possible.
+		return parseOdooRPCError(fmt.Errorf("failed to call Odoo method '%s' on model '%s': %w", method, model, err))
 	}
 	return nil
 }
@@ -106,7 +107,8 @@ func (c *OdooClient) executeRPC(ctx context.Context, model, method string, args
 //     Example: `godoo.Domain{{"name", "=", "John Doe"}, {"active", "=", true}}`
 //     For complex logical operations like OR/AND, proper nesting is required:
 //     `godoo.Domain{"&", {"is_company", "=", true}, {"|", {"email", "ilike", "%example.com"}, {"active", "=", false}}}`
-//   - options: Optional pointer to an Options struct to control search parameters like limit, offset, order, and context.
+//   - options: Optional pointers to Options structs to control search parameters like limit, offset, order, and context.
+//     Several Options are merged (see MergeOptions), e.g. `godoo.Limit(10), godoo.Order("name")`.
 //
 // Returns:
 //   - []int64: A slice of IDs of the records that match the search criteria.
@@ -158,20 +160,8 @@ func (c *OdooClient) SearchOne(ctx context.Context, model Model, domain Domain,
 		zap.String("op", "SearchOne"),
 	)
 
-	// Prepare options, ensuring Limit is set to 1.
-	searchOptions := &Options{Limit: 1}
-	if len(options) > 0 && options[0] != nil {
-		// Merge provided options, but ensure limit is 1.
-		mergedOptions := options[0].ToRPC()
-		mergedOptions["limit"] = 1
-		searchOptions = &Options{ // Recreate Options struct to pass to parseOptions
-			Limit:   1,
-			Offset:  options[0].Offset,
-			Order:   options[0].Order,
-			Context: options[0].Context,
-			Extra:   options[0].Extra,
-		}
-	}
+	// Merge provided options, but ensure limit is 1.
+	searchOptions := MergeOptions(append(options[:len(options):len(options)], Limit(1))...)
 
 	var ids []int64
 	err := c.executeRPC(ctx, string(model), "search", []interface{}{domain.ToRPC()}, searchOptions.ToRPC(), &ids)
@@ -204,7 +194,9 @@ func (c *OdooClient) SearchOne(ctx context.Context, model Model, domain Domain,
 }
 
 // Read performs a read operation on the specified Odoo model, retrieving records by their IDs.
-// It fetches specific fields for the given records.
+// It fetches specific fields for the given records. ID lists longer than the client's batch
+// size (see WithBatchSize) are read in chunks, possibly concurrently (see WithConcurrency),
+// and the records are returned in the order of ids.
 //
 // Parameters:
 //   - ctx: The context for the request.
@@ -234,10 +226,9 @@ func (c *OdooClient) Read(ctx context.Context, model Model, ids []int64, fields
 		return []map[string]interface{}{}, nil
 	}
 
-	var records []map[string]interface{}
 	// `fields.ToRPC()` correctly converts godoo.Fields to []string.
 	// `c.parseOptions(options...)` handles the optional Options struct.
-	err := c.executeRPC(ctx, string(model), "read", []interface{}{ids, fields.ToRPC()}, c.parseOptions(options...), &records)
+	records, err := c.readChunks(ctx, model, ids, fields.ToRPC(), c.parseOptions(options...))
 	if err != nil {
 		return nil, err
 	}
@@ -298,9 +289,11 @@ func (c *OdooClient) ReadOne(ctx context.Context, model Model, id int64, fields
 
 // ReadWithLimit performs a read operation on the specified Odoo model with IDs, fields, and options.
 // This is useful for fetching a subset of records when dealing with a large set of IDs,
-// and applying specific Odoo context or ordering. Note that this method does not perform an
-// internal 'search' prior to 'read'; it expects the IDs to be provided directly.
-// Use `Search` followed by `Read` if filtering is needed.
+// and applying specific Odoo context or ordering. It does not filter: use `Search` followed
+// by `Read` if filtering is needed. Offset and limit select the ids to read, which are then
+// read like in Read (in chunks when the selection is long). Without an order the ids keep
+// the given order; with one, they are sorted by a `search` restricted to them (archived
+// records included) before the page is taken, since `read` itself does not sort.
 //
 // Parameters:
 //   - ctx: The context for the request.
@@ -333,12 +326,27 @@ func (c *OdooClient) ReadWithLimit(ctx context.Context, model Model, ids []int64
 		options = &Options{} // Ensure options is not nil for ToRPC call
 	}
 
-	var records []map[string]interface{}
 	// `fields.ToRPC()` correctly converts godoo.Fields to []string.
 	// `options.ToRPC()` converts godoo.Options to map[string]interface{}.
-	err := c.executeRPC(ctx, string(model), "read", []interface{}{ids, fields.ToRPC()}, options.ToRPC(), &records)
-	if err != nil {
-		return nil, err
+	kwargs := options.ToRPC()
+	// `read` has no limit/offset/order: they select the ids, whether or not they are chunked.
+	delete(kwargs, "limit")
+	delete(kwargs, "offset")
+	delete(kwargs, "order")
+	if options.Order != "" {
+		var err error
+		if ids, err = c.orderIDs(ctx, model, ids, options); err != nil {
+			return nil, err
+		}
+	} else {
+		ids = pageIDs(ids, options.Offset, options.Limit)
+	}
+	records := []map[string]interface{}{}
+	if len(ids) > 0 {
+		var err error
+		if records, err = c.readChunks(ctx, model, ids, fields.ToRPC(), kwargs); err != nil {
+			return nil, err
+		}
 	}
 
 	c.logger.Info("Odoo readWithLimit completed",
@@ -456,6 +464,10 @@ func (c *OdooClient) Create(ctx context.Context, model Model, data []Data, optio
 //     Example: `godoo.Data{"name": "Updated Product Name", "price": 12.0}`.
 //   - options: Optional pointer to an Options struct to include additional context.
 //
+// ID lists longer than the client's batch size (see WithBatchSize) are written in chunks;
+// if a chunk fails, the chunks already written are not rolled back. Each chunk is applied
+// atomically by Odoo.
+//
 // Returns:
 //   - bool: `true` if the update operation was successful, `false` otherwise.
 //   - error: An error if the update fails, or if the response type is unexpected.
@@ -471,9 +483,11 @@ func (c *OdooClient) Update(ctx context.Context, model Model, ids []int64, data
 		return false, fmt.Errorf("godoo: no record IDs provided for update")
 	}
 
-	var success bool
 	// Odoo's 'write' method expects a list of IDs and a dictionary of data.
-	err := c.executeRPC(ctx, string(model), "write", []interface{}{ids, data.ToRPC()}, c.parseOptions(options...), &success)
+	vals := data.ToRPC()
+	success, err := c.boolChunks(ctx, ids, func(ctx context.Context, chunk []int64, reply *bool) error {
+		return c.executeRPC(ctx, string(model), "write", []interface{}{chunk, vals}, c.parseOptions(options...), reply)
+	})
 	if err != nil {
 		return false, err
 	}
@@ -490,12 +504,13 @@ func (c *OdooClient) Update(ctx context.Context, model Model, ids []int64, data
 // UpdateMultiple updates multiple existing records in the specified Odoo model,
 // allowing different data to be applied to each record.
 //
-// This function iterates through the provided map of IDs and their respective data,
-// making an individual Odoo RPC call for each record concurrently using goroutines.
-// This can improve performance for a large number of independent record updates.
+// Records receiving identical data are grouped into a single `write` call (e.g. setting
+// `active=false` on 5,000 records), groups larger than the client's batch size (see
+// WithBatchSize) are split into several calls, and the calls run with at most the client's
+// concurrency (see WithConcurrency) in flight.
 //
 // Parameters:
-//   - ctx: The context for the request, enabling cancellation and timeouts for each individual update.
+//   - ctx: The context for the request, enabling cancellation and timeouts for each individual write.
 //   - model: The Odoo model name where records will be updated.
 //   - idDataMap: A map where keys are the int64 IDs of the records to update,
 //     and values are Data maps containing the specific field-value
@@ -507,10 +522,9 @@ func (c *OdooClient) Update(ctx context.Context, model Model, ids []int64, data
 //     }
 //
 // Returns:
-//   - map[int64]error: A map indicating the success or failure for each ID.
-//     If an ID was updated successfully, its value in the map will be nil.
-//     If an error occurred for a specific ID, the error will be present.
-//     This map will be empty if idDataMap is empty or nil.
+//   - map[int64]error: The IDs whose update failed, with their error. A failed grouped
+//     write is split in halves and retried (Odoo rolls a failed write back) until every
+//     failing ID has its own error. The map is empty when all updates succeed.
 //   - error: An error if there's a fundamental issue before starting updates
 //     (e.g., connection failure), or if the main context is cancelled.
 //     Individual record errors are captured in the returned map.
@@ -529,50 +543,86 @@ func (c *OdooClient) UpdateMultiple(ctx context.Context, model Model, idDataMap
 		return map[int64]error{}, nil
 	}
 
-	resultsChan := make(chan struct {
-		ID  int64
-		Err error
-	}, len(idDataMap))
+	// One write per chunk of each group of identical data.
+	var writes []writeGroup
+	for _, group := range groupWrites(idDataMap) {
+		for _, ids := range chunkIDs(group.ids, c.batchSize) {
+			writes = append(writes, writeGroup{ids: ids, data: group.data})
+		}
+	}
 
-	var wg sync.WaitGroup
 	parsedOptions := c.parseOptions(options...) // Parse options once for all concurrent calls
+	failed := make([]map[int64]error, len(writes))
+	c.runConcurrently(ctx, len(writes), func(i int) {
+		failed[i] = map[int64]error{}
+		c.writeBisect(ctx, string(model), writes[i].ids, writes[i].data.ToRPC(), parsedOptions, failed[i])
+	})
 
-	for id, data := range idDataMap {
-		wg.Add(1)
-		go func(recordID int64, recordData Data) { // Changed to Data type
-			defer wg.Done()
-			var success bool
-			// `recordData.ToRPC()` converts godoo.Data to map[string]interface{}.
-			err := c.executeRPC(ctx, string(model), "write", []interface{}{[]int64{recordID}, recordData.ToRPC()}, parsedOptions, &success)
-			resultsChan <- struct {
-				ID  int64
-				Err error
-			}{ID: recordID, Err: err}
-		}(id, data)
+	if ctx.Err() != nil {
+		return nil, ctx.Err() // Return context error if the main context was cancelled
 	}
 
-	wg.Wait()
-	close(resultsChan)
-
 	failedUpdates := make(map[int64]error)
-	for res := range resultsChan {
-		if res.Err != nil {
-			failedUpdates[res.ID] = res.Err
-			c.logger.Error("Failed to update single record in Odoo updateMultiple",
-				zap.Int64("record_id", res.ID),
-				zap.String("model", string(model)),
-				zap.Error(res.Err),
-				zap.String("op", "UpdateMultiple"),
-			)
+	for _, errs := range failed {
+		for id, err := range errs {
+			failedUpdates[id] = err
 		}
 	}
 
-	if ctx.Err() != nil {
-		return nil, ctx.Err() // Return context error if the main context was cancelled
-	}
+	c.logger.Info("Odoo updateMultiple completed",
+		zap.String("model", string(model)),
+		zap.Int("records_count", len(idDataMap)),
+		zap.Int("write_calls", len(writes)),
+		zap.Int("failed_count", len(failedUpdates)),
+		zap.String("op", "UpdateMultiple"),
+	)
 	return failedUpdates, nil
 }
 
+// writeBisect writes values to ids, recording in failed the error of each id whose write
+// fails. A write of several ids that fails because of a record (a validation or user error)
+// is retried on each half, so one bad record does not take its whole group down; Odoo rolls
+// the failed write back, so nothing is applied twice. Any other error (access, network,
+// server) would fail every half too, so the whole group gets it at once.
+func (c *OdooClient) writeBisect(ctx context.Context, model string, ids []int64, values map[string]interface{}, options map[string]interface{}, failed map[int64]error) {
+	var success bool
+	err := c.executeRPC(ctx, model, "write", []interface{}{ids, values}, options, &success)
+	if err == nil {
+		return
+	}
+	if len(ids) == 1 || !isRecordError(err) {
+		c.failWrite(model, ids, err, failed)
+		return
+	}
+	c.logger.Debug("Grouped write failed, retrying each half",
+		zap.String("model", model),
+		zap.Int("records_count", len(ids)),
+		zap.Error(err),
+		zap.String("op", "UpdateMultiple"),
+	)
+	mid := len(ids) / 2
+	for _, half := range [][]int64{ids[:mid], ids[mid:]} {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			c.failWrite(model, half, ctxErr, failed)
+			continue
+		}
+		c.writeBisect(ctx, model, half, values, options, failed)
+	}
+}
+
+// failWrite logs a failed write and records err for each of ids.
+func (c *OdooClient) failWrite(model string, ids []int64, err error, failed map[int64]error) {
+	c.logger.Error("Failed to update records in Odoo updateMultiple",
+		zap.Any("record_ids", ids),
+		zap.String("model", model),
+		zap.Error(err),
+		zap.String("op", "UpdateMultiple"),
+	)
+	for _, id := range ids {
+		failed[id] = err
+	}
+}
+
 // Delete deletes records from the specified Odoo model.
 //
 // Parameters:
@@ -581,6 +631,10 @@ func (c *OdooClient) UpdateMultiple(ctx context.Context, model Model, idDataMap
 //   - ids: A slice of int64 representing the IDs of the records to delete.
 //   - options: Optional pointer to an Options struct to include additional context.
 //
+// ID lists longer than the client's batch size (see WithBatchSize) are deleted in chunks;
+// if a chunk fails, the chunks already deleted stay deleted. Each chunk is applied
+// atomically by Odoo.
+//
 // Returns:
 //   - bool: `true` if the deletion operation was successful, `false` otherwise.
 //   - error: An error if the deletion fails, or if the response type is unexpected.
@@ -595,9 +649,10 @@ func (c *OdooClient) Delete(ctx context.Context, model Model, ids []int64, optio
 		return false, fmt.Errorf("godoo: no record IDs provided for deletion")
 	}
 
-	var success bool
 	// Odoo's 'unlink' method expects a list of IDs.
-	err := c.executeRPC(ctx, string(model), "unlink", []interface{}{ids}, c.parseOptions(options...), &success)
+	success, err := c.boolChunks(ctx, ids, func(ctx context.Context, chunk []int64, reply *bool) error {
+		return c.executeRPC(ctx, string(model), "unlink", []interface{}{chunk}, c.parseOptions(options...), reply)
+	})
 	if err != nil {
 		return false, err
 	}
@@ -641,58 +696,9 @@ func (c *OdooClient) CallOdoo(ctx context.Context, model Model, method string, a
 		zap.String("op", "CallOdoo"),
 	)
 
-	uid, rpcClient, err := c.getConnection(ctx)
-	if err != nil {
-		c.logger.Error("Failed to get Odoo connection for custom RPC call",
-			zap.Error(err),
-			zap.String("model", string(model)),
-			zap.String("method", method),
-			zap.String("op", "CallOdoo"),
-		)
-		return nil, err
-	}
-
 	var result interface{} // The response can be of any type
-
-	// Construct the arguments for the rpcClient.Call("execute_kw", ...)
-	// This mirrors the structure: (db, uid, password, model, method, args[], options{})
-	rpcCallArgs := []interface{}{c.db, uid, c.password, string(model), method, args}
-
-	// Append options (kwargs) if provided, otherwise an empty map.
-	// `execute_kw` always expects a kwargs dictionary, even if empty.
-	if len(options) > 0 {
-		rpcCallArgs = append(rpcCallArgs, options)
-	} else {
-		rpcCallArgs = append(rpcCallArgs, map[string]interface{}{})
-	}
-
-	callChan := make(chan error, 1)
-	go func() {
-		// Execute the RPC call. The result will be unmarshalled into 'result'
-		callErr := rpcClient.Call("execute_kw", rpcCallArgs, &result)
-		callChan <- callErr
-	}()
-
-	select {
-	case <-ctx.Done():
-		c.logger.Error("Custom Odoo RPC call cancelled by context timeout/cancellation",
-			zap.Error(ctx.Err()),
-			zap.String("model", string(model)),
-			zap.String("method", method),
-			zap.String("op", "CallOdoo"),
-		)
-		return nil, ctx.Err()
-	case err = <-callChan:
-		// The RPC call completed (successfully or with an error)
-		if err != nil {
-			c.logger.Error("Failed to execute custom Odoo RPC call",
-				zap.Error(err),
-				zap.String("model", string(model)),
-				zap.String("method", method),
-				zap.String("op", "CallOdoo"),
-			)
-			return nil, parseOdooRPCError(fmt.Errorf("failed to call Odoo method '%s' on model '%s': %w", method, string(model), err))
-		}
+	if err := c.executeRPC(ctx, string(model), method, args, options, &result); err != nil {
+		return nil, err
 	}
 
 	c.logger.Info("Custom Odoo RPC call completed",
@@ -0,0 +1,222 @@
+// godoo/crud_test.go
+package godoo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUpdateMultipleAttribution(t *testing.T) {
+	const bad = 5
+	transports := []struct {
+		name string
+		opts []Option
+	}{
+		{"xmlrpc", nil},
+		{"jsonrpc", []Option{WithTransport(TransportJSONRPC)}},
+	}
+	tests := []struct {
+		name string
+		// fault is the error of a write including the bad record.
+		fault *fakeFault
+		// bisect tells whether the group is split to find the bad record.
+		bisect bool
+	}{
+		{"validation error", &fakeFault{"odoo.exceptions.ValidationError", "Invalid email"}, true},
+		{"user error", &fakeFault{"odoo.exceptions.UserError", "Record is locked"}, true},
+		{"access error", &fakeFault{"odoo.exceptions.AccessError", "Not allowed"}, false},
+		{"server error", &fakeFault{"builtins.TypeError", "unhashable type"}, false},
+	}
+	for _, tr := range transports {
+		for _, tt := range tests {
+			t.Run(tr.name+"/"+tt.name, func(t *testing.T) {
+				srv := newFakeOdoo(t, "s3cret")
+				store := newFakeRecords()
+				updates := map[int64]Data{}
+				for i := 0; i < 8; i++ {
+					id := store.insert(map[string]interface{}{"name": "old"})
+					updates[id] = Data{"name": "new"}
+				}
+				srv.handle = func(call fakeCall) (interface{}, error) {
+					if call.Method == "write" {
+						for _, id := range fakeIDs(call.Args[0]) {
+							if id == bad {
+								return nil, tt.fault
+							}
+						}
+					}
+					return store.handle(call)
+				}
+				c := srv.client(t, tr.opts...)
+
+				failed, err := c.UpdateMultiple(context.Background(), ModelResPartner, updates)
+				if err != nil {
+					t.Fatalf("UpdateMultiple: %v", err)
+				}
+				writes := 0
+				for _, call := range srv.received() {
+					if call.Method == "write" {
+						writes++
+					}
+				}
+
+				if !tt.bisect {
+					if len(failed) != len(updates) {
+						t.Errorf("failed = %v, want every record", failed)
+					}
+					for id, ferr := range failed {
+						if ferr == nil || !strings.Contains(ferr.Error(), tt.fault.Message) {
+							t.Errorf("record %d failed with %v, want %q", id, ferr, tt.fault.Message)
+						}
+					}
+					if writes != 1 {
+						t.Errorf("%d write calls, want 1 without bisecting", writes)
+					}
+					return
+				}
+
+				if len(failed) != 1 || failed[bad] == nil || !strings.Contains(failed[bad].Error(), tt.fault.Message) {
+					t.Errorf("failed = %v, want only record %d with %q", failed, bad, tt.fault.Message)
+				}
+				for id := range updates {
+					if rec := store.get(id); id != bad && rec["name"] != "new" {
+						t.Errorf("record %d = %v, want it written", id, rec)
+					}
+				}
+				if rec := store.get(bad); rec["name"] != "old" {
+					t.Errorf("bad record = %v, want it untouched", rec)
+				}
+				// 8 -> 4 -> 2 -> 1 on the bad side, one good write at each level.
+				if writes != 7 {
+					t.Errorf("%d write calls, want 7", writes)
+				}
+			})
+		}
+	}
+}
+
+func TestUpdateMultipleStopsBisectingWhenCanceled(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv.handle = func(call fakeCall) (interface{}, error) {
+		if call.Method == "write" {
+			cancel()
+			return nil, &fakeFault{"odoo.exceptions.ValidationError", "Invalid email"}
+		}
+		return true, nil
+	}
+	c := srv.client(t)
+	updates := map[int64]Data{1: {"name": "new"}, 2: {"name": "new"}, 3: {"name": "new"}, 4: {"name": "new"}}
+
+	if _, err := c.UpdateMultiple(ctx, ModelResPartner, updates); !errors.Is(err, context.Canceled) {
+		t.Fatalf("UpdateMultiple error = %v, want context.Canceled", err)
+	}
+	if calls := srv.received(); len(calls) != 1 {
+		t.Errorf("received %d calls after cancellation, want the first write only", len(calls))
+	}
+}
+
+func TestWritesChunkedByDefault(t *testing.T) {
+	ids := make([]int64, DefaultBatchSize+1)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	tests := []struct {
+		name string
+		opts []Option
+		want int // write and unlink calls
+	}{
+		{"default", nil, 2},
+		{"batch size", []Option{WithBatchSize(400)}, 3},
+		{"disabled", []Option{WithBatchSize(0)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeOdoo(t, "s3cret")
+			c := srv.client(t, tt.opts...)
+			ctx := context.Background()
+			if _, err := c.Update(ctx, ModelResPartner, ids, Data{"active": false}); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			if _, err := c.Delete(ctx, ModelResPartner, ids); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			updates := make(map[int64]Data, len(ids))
+			for _, id := range ids {
+				updates[id] = Data{"active": true}
+			}
+			if _, err := c.UpdateMultiple(ctx, ModelResPartner, updates); err != nil {
+				t.Fatalf("UpdateMultiple: %v", err)
+			}
+			counts := map[string]int{}
+			for _, call := range srv.received() {
+				counts[call.Method]++
+			}
+			if counts["write"] != 2*tt.want || counts["unlink"] != tt.want {
+				t.Errorf("calls = %v, want %d writes per operation and %d unlinks", counts, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadWithLimit(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	store := newFakeRecords(
+		map[string]interface{}{"name": "Delta"},
+		map[string]interface{}{"name": "Alpha"},
+		map[string]interface{}{"name": "Charlie", "active": false},
+		map[string]interface{}{"name": "Bravo"},
+		map[string]interface{}{"name": "Echo"},
+	)
+	srv.handle = store.handle
+	c := srv.client(t)
+	ctx := context.Background()
+	names := func(records []map[string]interface{}) []string {
+		out := []string{}
+		for _, r := range records {
+			out = append(out, r["name"].(string))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		ids     []int64
+		options *Options
+		want    []string
+	}{
+		{"given order", []int64{5, 1, 2}, &Options{Limit: 2}, []string{"Echo", "Delta"}},
+		{"given order with offset", []int64{5, 1, 2}, &Options{Offset: 1}, []string{"Delta", "Alpha"}},
+		{"sorted", []int64{1, 2, 3, 4}, &Options{Order: "name asc"}, []string{"Alpha", "Bravo", "Charlie", "Delta"}},
+		{"sorted page", []int64{1, 2, 3, 4, 5}, &Options{Order: "name desc", Offset: 1, Limit: 2}, []string{"Delta", "Charlie"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := c.ReadWithLimit(ctx, ModelResPartner, tt.ids, Fields{"name"}, tt.options)
+			if err != nil {
+				t.Fatalf("ReadWithLimit: %v", err)
+			}
+			if got := names(records); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ReadWithLimit = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, call := range srv.received() {
+		switch call.Method {
+		case "read":
+			for _, k := range []string{"order", "limit", "offset"} {
+				if _, ok := call.Kwargs[k]; ok {
+					t.Errorf("read was called with %s: %v", k, call.Kwargs)
+				}
+			}
+		case "search":
+			if ctx, _ := call.Kwargs["context"].(map[string]interface{}); ctx["active_test"] != false {
+				t.Errorf("search of the ids kept active_test: %v", call.Kwargs)
+			}
+		}
+	}
+}
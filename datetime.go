@@ -0,0 +1,273 @@
+// godoo/datetime.go
+package godoo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formats of Odoo's date and datetime fields. Datetimes are naive strings in UTC.
+const (
+	DateFormat     = "2006-01-02"
+	DateTimeFormat = "2006-01-02 15:04:05"
+)
+
+// Date is the value of an Odoo date field. The zero Date is an empty field: it reads from
+// `false` and is written as `false`.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the Date year-month-day.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf returns the calendar date of t in t's location.
+func DateOf(t time.Time) Date {
+	if t.IsZero() {
+		return Date{}
+	}
+	return NewDate(t.Year(), t.Month(), t.Day())
+}
+
+// ParseDate parses a date in Odoo format ("2024-05-01"). A datetime string is accepted and
+// truncated to its date; an empty string yields the zero Date.
+func ParseDate(s string) (Date, error) {
+	if s == "" {
+		return Date{}, nil
+	}
+	if len(s) > len(DateFormat) {
+		s = s[:len(DateFormat)]
+	}
+	t, err := time.Parse(DateFormat, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("godoo: invalid Odoo date %q: %w", s, err)
+	}
+	return Date{t}, nil
+}
+
+// String returns the date in Odoo format, or "" for the zero Date.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateFormat)
+}
+
+// Equal reports whether d and other are the same calendar date.
+func (d Date) Equal(other Date) bool {
+	return d.String() == other.String()
+}
+
+// StartIn returns the instant the date starts (midnight) in loc, e.g. to filter a datetime
+// field by the days of the user's time zone.
+func (d Date) StartIn(loc *time.Location) DateTime {
+	if d.IsZero() {
+		return DateTime{}
+	}
+	return DateTimeOf(time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc))
+}
+
+// UnmarshalOdoo decodes "2024-05-01" or `false`.
+func (d *Date) UnmarshalOdoo(value interface{}) error {
+	switch v := value.(type) {
+	case nil, bool:
+		*d = Date{}
+		return nil
+	case string:
+		parsed, err := ParseDate(v)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	}
+	return fmt.Errorf("%w: cannot decode %T into Date", ErrInvalidResponse, value)
+}
+
+// MarshalOdoo encodes the date in Odoo format, or false for the zero Date.
+func (d Date) MarshalOdoo() (interface{}, error) {
+	if d.IsZero() {
+		return false, nil
+	}
+	return d.String(), nil
+}
+
+// MarshalJSON encodes the date as a "2024-05-01" string, or null for the zero Date.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a "2024-05-01" string, null or false.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalJSONTime(data)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// DateTime is the value of an Odoo datetime field, always held in UTC. The zero DateTime
+// is an empty field: it reads from `false` and is written as `false`.
+type DateTime struct {
+	time.Time
+}
+
+// DateTimeOf returns t as a DateTime (converted to UTC, truncated to seconds as Odoo stores).
+func DateTimeOf(t time.Time) DateTime {
+	if t.IsZero() {
+		return DateTime{}
+	}
+	return DateTime{t.UTC().Truncate(time.Second)}
+}
+
+// ParseDateTime parses a datetime in Odoo format ("2024-05-01 13:45:00", UTC). A date-only
+// string means midnight UTC; an empty string yields the zero DateTime.
+func ParseDateTime(s string) (DateTime, error) {
+	return ParseDateTimeIn(s, time.UTC)
+}
+
+// ParseDateTimeIn parses a wall-clock time in Odoo format as seen in loc (e.g. typed by a
+// user in their time zone) and returns the instant it denotes. DST transitions are
+// resolved by the time package.
+func ParseDateTimeIn(s string, loc *time.Location) (DateTime, error) {
+	if s == "" {
+		return DateTime{}, nil
+	}
+	layout := DateTimeFormat
+	switch {
+	case len(s) == len(DateFormat):
+		layout = DateFormat
+	case len(s) > len(DateTimeFormat) && s[len(DateTimeFormat)] == '.':
+		layout = DateTimeFormat + ".999999"
+	case strings.Contains(s, "T"):
+		layout = "2006-01-02T15:04:05"
+	}
+	t, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return DateTime{}, fmt.Errorf("godoo: invalid Odoo datetime %q: %w", s, err)
+	}
+	return DateTimeOf(t), nil
+}
+
+// String returns the datetime in Odoo format (UTC), or "" for the zero DateTime.
+func (dt DateTime) String() string {
+	if dt.IsZero() {
+		return ""
+	}
+	return dt.UTC().Format(DateTimeFormat)
+}
+
+// Equal reports whether dt and other denote the same instant.
+func (dt DateTime) Equal(other DateTime) bool {
+	return dt.Time.Equal(other.Time)
+}
+
+// In returns the datetime in the time zone loc.
+func (dt DateTime) In(loc *time.Location) time.Time {
+	return dt.Time.In(loc)
+}
+
+// InContext returns the datetime in the time zone of the context's `tz` (UTC if unset),
+// which is how Odoo displays it to the user.
+func (dt DateTime) InContext(oc OdooContext) (time.Time, error) {
+	loc, err := oc.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dt.In(loc), nil
+}
+
+// LocalDate returns the calendar date of the datetime in loc.
+func (dt DateTime) LocalDate(loc *time.Location) Date {
+	if dt.IsZero() {
+		return Date{}
+	}
+	return DateOf(dt.In(loc))
+}
+
+// UnmarshalOdoo decodes "2024-05-01 13:45:00" (UTC) or `false`.
+func (dt *DateTime) UnmarshalOdoo(value interface{}) error {
+	switch v := value.(type) {
+	case nil, bool:
+		*dt = DateTime{}
+		return nil
+	case string:
+		parsed, err := ParseDateTime(v)
+		if err != nil {
+			return err
+		}
+		*dt = parsed
+		return nil
+	}
+	return fmt.Errorf("%w: cannot decode %T into DateTime", ErrInvalidResponse, value)
+}
+
+// MarshalOdoo encodes the datetime in Odoo format (UTC), or false for the zero DateTime.
+func (dt DateTime) MarshalOdoo() (interface{}, error) {
+	if dt.IsZero() {
+		return false, nil
+	}
+	return dt.String(), nil
+}
+
+// MarshalJSON encodes the datetime as a "2024-05-01 13:45:00" string, or null when zero.
+func (dt DateTime) MarshalJSON() ([]byte, error) {
+	if dt.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(dt.String())
+}
+
+// UnmarshalJSON decodes a "2024-05-01 13:45:00" string, null or false.
+func (dt *DateTime) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalJSONTime(data)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseDateTime(s)
+	if err != nil {
+		return err
+	}
+	*dt = parsed
+	return nil
+}
+
+// unmarshalJSONTime returns the string of a JSON date value; null and false yield "".
+func unmarshalJSONTime(data []byte) (string, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) || bytes.Equal(data, []byte("false")) {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", fmt.Errorf("godoo: invalid Odoo date value %s: %w", data, err)
+	}
+	return s, nil
+}
+
+// Location returns the time zone of the context's `tz` key (an IANA name such as
+// "America/Caracas"), or UTC when it is not set.
+func (oc OdooContext) Location() (*time.Location, error) {
+	tz, _ := oc["tz"].(string)
+	if tz == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("godoo: unknown time zone %q in context: %w", tz, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,408 @@
+// godoo/dbservice.go
+package godoo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DumpFormat is the archive format produced by DBService.Dump.
+type DumpFormat string
+
+const (
+	// DumpZip is a zip with the SQL dump and the filestore (restorable from the database manager).
+	DumpZip DumpFormat = "zip"
+	// DumpPostgres is a pg_dump custom-format archive without the filestore.
+	DumpPostgres DumpFormat = "dump"
+)
+
+// CreateDatabaseOptions are the parameters of DBService.Create.
+type CreateDatabaseOptions struct {
+	Demo          bool   // Load demonstration data
+	Lang          string // Default language, e.g. "en_US" (default)
+	AdminLogin    string // Login of the admin user (default "admin")
+	AdminPassword string // Password of the admin user (required)
+	CountryCode   string // Optional ISO country code of the main company
+	Phone         string // Optional phone of the main company
+}
+
+// DBService wraps Odoo's `db` service (database provisioning), authenticated with the
+// master password. Dumps and restores are streamed through the /web/database routes, so
+// backups are never held in memory; the other calls go through /jsonrpc.
+//
+// The database manager must be enabled on the server (`list_db` is only needed for List).
+type DBService struct {
+	client *OdooClient
+
+	mu             sync.Mutex // guards masterPassword, replaced by ChangeAdminPassword
+	masterPassword CredentialProvider
+}
+
+// WithDBTimeout establece el tiempo máximo de cada operación de DBService, incluidos los
+// volcados y restauraciones, que pueden tardar mucho más que una llamada normal. Sin esta
+// opción las llamadas al servicio `db` usan el límite de WithTimeout, y Dump y Restore no
+// tienen límite propio (sólo el del contexto).
+func WithDBTimeout(d time.Duration) Option {
+	return func(c *OdooClient) {
+		c.dbTimeout = d
+	}
+}
+
+// callContext bounds ctx by the DBService timeout (see WithDBTimeout), or by the client's
+// call timeout when none is set and fallback is true.
+func (s *DBService) callContext(ctx context.Context, fallback bool) (context.Context, context.CancelFunc) {
+	if s.client.dbTimeout > 0 {
+		return context.WithTimeout(ctx, s.client.dbTimeout)
+	}
+	if fallback {
+		return s.client.callContext(ctx)
+	}
+	return ctx, func() {}
+}
+
+// NewDBService creates a DBService for the Odoo server at urlStr. The Options configure the
+// HTTP client, TLS verification and logging exactly as for New.
+func NewDBService(urlStr, masterPassword string, opts ...Option) (*DBService, error) {
+	client, err := New(urlStr, "", "", "", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &DBService{client: client, masterPassword: StaticCredential(masterPassword)}, nil
+}
+
+// DBService returns a DBService for the client's server, sharing its HTTP client and logger.
+func (c *OdooClient) DBService(masterPassword CredentialProvider) *DBService {
+	return &DBService{client: c, masterPassword: masterPassword}
+}
+
+// call invokes a method of the `db` service through /jsonrpc.
+func (s *DBService) call(ctx context.Context, method string, args []interface{}, reply interface{}) error {
+	s.client.logger.Debug("Performing Odoo db service call",
+		zap.String("method", method),
+		zap.String("op", "DBService"),
+	)
+	ctx, cancel := s.callContext(ctx, true)
+	defer cancel()
+	params := map[string]interface{}{
+		"service": "db",
+		"method":  method,
+		"args":    args,
+	}
+	if err := postJSONRPC(ctx, s.client.httpClient, s.client.url+"/jsonrpc", params, reply); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.client.logger.Error("Odoo db service call failed",
+			zap.Error(err),
+			zap.String("method", method),
+			zap.String("op", "DBService"),
+		)
+		return parseOdooRPCError(fmt.Errorf("failed to call db service method '%s': %w", method, err))
+	}
+	return nil
+}
+
+// master returns the current master password.
+func (s *DBService) master(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	provider := s.masterPassword
+	s.mu.Unlock()
+	if provider == nil {
+		return "", fmt.Errorf("%w: no master password configured", ErrMissingCredential)
+	}
+	return provider.Secret(ctx)
+}
+
+// List returns the names of the databases on the server. Fails when `list_db` is disabled.
+func (s *DBService) List(ctx context.Context) ([]string, error) {
+	var names []string
+	if err := s.call(ctx, "list", []interface{}{}, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Exists reports whether the database `name` exists.
+func (s *DBService) Exists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	if err := s.call(ctx, "db_exist", []interface{}{name}, &exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// ServerVersion returns the raw server version string, e.g. "17.0" or "saas~17.2+e".
+func (s *DBService) ServerVersion(ctx context.Context) (string, error) {
+	var version string
+	if err := s.call(ctx, "server_version", []interface{}{}, &version); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// Create creates a new database and its admin user.
+func (s *DBService) Create(ctx context.Context, name string, opts CreateDatabaseOptions) error {
+	if opts.AdminPassword == "" {
+		return fmt.Errorf("godoo: an admin password is required to create database '%s'", name)
+	}
+	if opts.Lang == "" {
+		opts.Lang = "en_US"
+	}
+	if opts.AdminLogin == "" {
+		opts.AdminLogin = "admin"
+	}
+	master, err := s.master(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := []interface{}{master, name, opts.Demo, opts.Lang, opts.AdminPassword, opts.AdminLogin}
+	if opts.CountryCode != "" || opts.Phone != "" {
+		args = append(args, opts.CountryCode, opts.Phone)
+	}
+	var ok bool
+	if err := s.call(ctx, "create_database", args, &ok); err != nil {
+		return err
+	}
+	s.client.logger.Info("Odoo database created", zap.String("db", name), zap.String("op", "DBService.Create"))
+	return nil
+}
+
+// Duplicate copies database `source` into a new database `target`. With neutralize
+// (Odoo 16+), outgoing mail servers, crons and payment providers are disabled in the copy.
+func (s *DBService) Duplicate(ctx context.Context, source, target string, neutralize bool) error {
+	master, err := s.master(ctx)
+	if err != nil {
+		return err
+	}
+	args := []interface{}{master, source, target}
+	if neutralize {
+		args = append(args, true)
+	}
+	var ok bool
+	if err := s.call(ctx, "duplicate_database", args, &ok); err != nil {
+		return err
+	}
+	s.client.logger.Info("Odoo database duplicated",
+		zap.String("source", source),
+		zap.String("db", target),
+		zap.String("op", "DBService.Duplicate"),
+	)
+	return nil
+}
+
+// Drop deletes the database `name`.
+func (s *DBService) Drop(ctx context.Context, name string) error {
+	master, err := s.master(ctx)
+	if err != nil {
+		return err
+	}
+	var ok bool
+	if err := s.call(ctx, "drop", []interface{}{master, name}, &ok); err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: database '%s' could not be dropped", ErrRecordNotFound, name)
+	}
+	s.client.logger.Info("Odoo database dropped", zap.String("db", name), zap.String("op", "DBService.Drop"))
+	return nil
+}
+
+// ChangeAdminPassword replaces the master password with newPassword. Subsequent calls of
+// this DBService use the new password. It is safe to call concurrently with other methods.
+func (s *DBService) ChangeAdminPassword(ctx context.Context, newPassword string) error {
+	master, err := s.master(ctx)
+	if err != nil {
+		return err
+	}
+	var ok bool
+	if err := s.call(ctx, "change_admin_password", []interface{}{master, newPassword}, &ok); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.masterPassword = StaticCredential(newPassword)
+	s.mu.Unlock()
+	return nil
+}
+
+// Dump streams a backup of database `name` in the given format to w through
+// /web/database/backup and returns the number of bytes written.
+func (s *DBService) Dump(ctx context.Context, name string, format DumpFormat, w io.Writer) (int64, error) {
+	if format == "" {
+		format = DumpZip
+	}
+	ctx, cancel := s.callContext(ctx, false)
+	defer cancel()
+	master, err := s.master(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	form := url.Values{
+		"master_pwd":    {master},
+		"name":          {name},
+		"backup_format": {string(format)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.client.url+"/web/database/backup", strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, fmt.Errorf("godoo: database backup request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// On failure Odoo renders the database manager page (HTML) with the error message.
+	if err := databaseManagerError(resp); err != nil {
+		return 0, fmt.Errorf("godoo: backup of database '%s' failed: %w", name, err)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("godoo: failed to stream backup of database '%s': %w", name, err)
+	}
+	s.client.logger.Info("Odoo database dumped",
+		zap.String("db", name),
+		zap.String("format", string(format)),
+		zap.Int64("bytes", n),
+		zap.String("op", "DBService.Dump"),
+	)
+	return n, nil
+}
+
+// Restore creates database `name` from a backup read from r, streamed as a multipart upload
+// to /web/database/restore. Set asCopy when restoring a duplicate of an existing database so
+// Odoo generates a new database UUID.
+func (s *DBService) Restore(ctx context.Context, name string, r io.Reader, asCopy bool) error {
+	ctx, cancel := s.callContext(ctx, false)
+	defer cancel()
+	master, err := s.master(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, contentType := streamMultipart(map[string]string{
+		"master_pwd": master,
+		"name":       name,
+		"copy":       fmt.Sprintf("%t", asCopy),
+	}, "backup_file", name+".zip", r)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.client.url+"/web/database/restore", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	// A successful restore redirects to the database manager; do not follow it so that
+	// success (redirect) and failure (manager page rendered with an error) can be told apart.
+	hc := *s.client.httpClient
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("godoo: database restore request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 300 || resp.StatusCode > 399 {
+		if err := databaseManagerError(resp); err != nil {
+			return fmt.Errorf("godoo: restore of database '%s' failed: %w", name, err)
+		}
+		return fmt.Errorf("godoo: restore of database '%s' failed: unexpected HTTP %d", name, resp.StatusCode)
+	}
+	s.client.logger.Info("Odoo database restored", zap.String("db", name), zap.String("op", "DBService.Restore"))
+	return nil
+}
+
+// streamMultipart encodes fields and one file part as multipart/form-data through a pipe,
+// so the file content is never buffered in memory.
+func streamMultipart(fields map[string]string, fileField, fileName string, file io.Reader) (io.Reader, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		for k, v := range fields {
+			if err := mw.WriteField(k, v); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		part, err := mw.CreateFormFile(fileField, fileName)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(mw.Close())
+	}()
+	return pr, mw.FormDataContentType()
+}
+
+// databaseManagerError turns a non-binary answer of the /web/database routes into an error.
+func databaseManagerError(resp *http.Response) error {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode == http.StatusOK && mediaType != "text/html" {
+		return nil
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	msg := extractAlert(string(raw))
+	if resp.StatusCode == http.StatusForbidden || strings.Contains(msg, "Access Denied") || strings.Contains(msg, "AccessDenied") {
+		return fmt.Errorf("%w: %s", ErrAuthenticationFailed, msg)
+	}
+	if msg == "" {
+		msg = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+	return errors.New(msg)
+}
+
+// extractAlert pulls the error text out of the database manager page
+// (rendered inside `<div class="alert alert-danger">`).
+func extractAlert(html string) string {
+	i := strings.Index(html, "alert-danger")
+	if i < 0 {
+		return ""
+	}
+	rest := html[i:]
+	if j := strings.Index(rest, ">"); j >= 0 {
+		rest = rest[j+1:]
+	}
+	if k := strings.Index(rest, "</div>"); k >= 0 {
+		rest = rest[:k]
+	}
+	// Drop any nested tags and collapse whitespace.
+	var b strings.Builder
+	inTag := false
+	for _, r := range rest {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
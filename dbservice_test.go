@@ -0,0 +1,211 @@
+// godoo/dbservice_test.go
+package godoo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDatabases serves the db service and the /web/database routes of a fakeOdoo.
+type fakeDatabases struct {
+	mu     sync.Mutex
+	master string
+	dbs    map[string]string // name -> content of its backup
+}
+
+func newFakeDatabases(srv *fakeOdoo, master string) *fakeDatabases {
+	d := &fakeDatabases{master: master, dbs: map[string]string{"test": "backup of test"}}
+	srv.db = d.call
+	srv.mux.HandleFunc("/web/database/backup", d.serveBackup)
+	srv.mux.HandleFunc("/web/database/restore", d.serveRestore)
+	return d
+}
+
+func (d *fakeDatabases) call(method string, args []interface{}) (interface{}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch method {
+	case "list":
+		names := []string{}
+		for name := range d.dbs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	case "db_exist":
+		_, ok := d.dbs[fmt.Sprint(args[0])]
+		return ok, nil
+	case "server_version":
+		return "17.0", nil
+	}
+	if len(args) == 0 || args[0] != d.master {
+		return nil, &fakeFault{"odoo.exceptions.AccessDenied", "Access Denied"}
+	}
+	switch method {
+	case "create_database":
+		d.dbs[fmt.Sprint(args[1])] = "backup of " + fmt.Sprint(args[1])
+	case "duplicate_database":
+		d.dbs[fmt.Sprint(args[2])] = d.dbs[fmt.Sprint(args[1])]
+	case "drop":
+		name := fmt.Sprint(args[1])
+		if _, ok := d.dbs[name]; !ok {
+			return false, nil
+		}
+		delete(d.dbs, name)
+	case "change_admin_password":
+		d.master = fmt.Sprint(args[1])
+	default:
+		return nil, &fakeFault{"werkzeug.exceptions.NotFound", method}
+	}
+	return true, nil
+}
+
+// managerPage renders the database manager page with an error, as Odoo does on failure.
+func managerPage(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<html><body><div class="alert alert-danger" role="alert">%s</div></body></html>`, msg)
+}
+
+func (d *fakeDatabases) serveBackup(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	master := d.master
+	content, ok := d.dbs[r.FormValue("name")]
+	d.mu.Unlock()
+	switch {
+	case r.FormValue("master_pwd") != master:
+		managerPage(w, "Database backup error: Access Denied")
+	case !ok:
+		managerPage(w, "Database backup error: database does not exist")
+	default:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		io.WriteString(w, content)
+	}
+}
+
+func (d *fakeDatabases) serveRestore(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("backup_file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	content, _ := io.ReadAll(file)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	name := r.FormValue("name")
+	switch {
+	case r.FormValue("master_pwd") != d.master:
+		managerPage(w, "Database restore error: Access Denied")
+	case d.dbs[name] != "":
+		managerPage(w, "Database restore error: database already exists")
+	default:
+		d.dbs[name] = string(content)
+		http.Redirect(w, r, "/web/database/manager", http.StatusSeeOther)
+	}
+}
+
+func TestDBServiceProvisioning(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	newFakeDatabases(srv, "master")
+	s := srv.client(t).DBService(StaticCredential("master"))
+	ctx := context.Background()
+
+	if err := s.Create(ctx, "acme", CreateDatabaseOptions{AdminPassword: "admin"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := s.Duplicate(ctx, "acme", "acme_staging", true); err != nil {
+		t.Fatalf("Duplicate: %v", err)
+	}
+	names, err := s.List(ctx)
+	if err != nil || strings.Join(names, ",") != "acme,acme_staging,test" {
+		t.Errorf("List = %v, %v; want [acme acme_staging test]", names, err)
+	}
+	if err := s.Drop(ctx, "acme_staging"); err != nil {
+		t.Fatalf("Drop: %v", err)
+	}
+	if exists, err := s.Exists(ctx, "acme_staging"); err != nil || exists {
+		t.Errorf("Exists after Drop = %v, %v; want false", exists, err)
+	}
+	if err := s.Drop(ctx, "acme_staging"); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("Drop of a missing database: error = %v, want ErrRecordNotFound", err)
+	}
+	if err := s.Create(ctx, "acme", CreateDatabaseOptions{}); err == nil {
+		t.Error("Create without an admin password succeeded")
+	}
+
+	wrong := srv.client(t).DBService(StaticCredential("wrong"))
+	if err := wrong.Drop(ctx, "acme"); err == nil || !strings.Contains(err.Error(), "Access Denied") {
+		t.Errorf("Drop with a wrong master password: error = %v, want Access Denied", err)
+	}
+	if err := srv.client(t).DBService(nil).Drop(ctx, "acme"); !errors.Is(err, ErrMissingCredential) {
+		t.Errorf("Drop without a master password: error = %v, want ErrMissingCredential", err)
+	}
+}
+
+func TestDBServiceChangeAdminPassword(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	newFakeDatabases(srv, "master")
+	s := srv.client(t).DBService(StaticCredential("master"))
+	ctx := context.Background()
+
+	// Calls running while the password changes read either password, never a torn value.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Exists(ctx, "test")
+			s.master(ctx)
+		}()
+	}
+	if err := s.ChangeAdminPassword(ctx, "rotated"); err != nil {
+		t.Fatalf("ChangeAdminPassword: %v", err)
+	}
+	wg.Wait()
+
+	if err := s.Create(ctx, "acme", CreateDatabaseOptions{AdminPassword: "admin"}); err != nil {
+		t.Errorf("Create with the new master password: %v", err)
+	}
+}
+
+func TestDBServiceDumpAndRestore(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	newFakeDatabases(srv, "master")
+	s := srv.client(t).DBService(StaticCredential("master"))
+	ctx := context.Background()
+
+	var backup strings.Builder
+	n, err := s.Dump(ctx, "test", DumpZip, &backup)
+	if err != nil || n != int64(len("backup of test")) || backup.String() != "backup of test" {
+		t.Fatalf("Dump = %d, %q, %v; want the backup of test", n, backup.String(), err)
+	}
+	if _, err := s.Dump(ctx, "missing", DumpZip, io.Discard); err == nil || !strings.Contains(err.Error(), "database does not exist") {
+		t.Errorf("Dump of a missing database: error = %v, want the manager page's message", err)
+	}
+	wrong := srv.client(t).DBService(StaticCredential("wrong"))
+	if _, err := wrong.Dump(ctx, "test", DumpZip, io.Discard); !errors.Is(err, ErrAuthenticationFailed) {
+		t.Errorf("Dump with a wrong master password: error = %v, want ErrAuthenticationFailed", err)
+	}
+
+	if err := s.Restore(ctx, "copy", strings.NewReader(backup.String()), true); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	var restored strings.Builder
+	if _, err := s.Dump(ctx, "copy", DumpZip, &restored); err != nil || restored.String() != "backup of test" {
+		t.Errorf("Dump of the restored database = %q, %v", restored.String(), err)
+	}
+	if err := s.Restore(ctx, "copy", strings.NewReader("x"), true); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Restore over an existing database: error = %v, want the manager page's message", err)
+	}
+}
@@ -0,0 +1,209 @@
+// godoo/decode.go
+package godoo
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// normalizeJSON walks a value decoded with json.Decoder.UseNumber and converts every
+// json.Number into int64 (when integral) or float64, so JSON-based transports hand out
+// the same Go types as the XML-RPC codec does (ids as int64, amounts as float64).
+func normalizeJSON(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(t), 10, 64); err == nil {
+			return i
+		}
+		f, _ := strconv.ParseFloat(string(t), 64)
+		return f
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeJSON(t[i])
+		}
+		return t
+	case map[string]interface{}:
+		for k := range t {
+			t[k] = normalizeJSON(t[k])
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// assignReply stores a generic, already-normalized RPC result into `reply`, which must be
+// a non-nil pointer (e.g. *[]int64, *bool, *[]map[string]interface{} or *interface{}).
+func assignReply(src interface{}, reply interface{}) error {
+	rv := reflect.ValueOf(reply)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("%w: reply must be a non-nil pointer, got %T", ErrInvalidResponse, reply)
+	}
+	return assignValue(src, rv.Elem())
+}
+
+// assignValue converts src into the type of dst and stores it there. Unmarshaler types
+// decode themselves, Odoo's `false` for an empty field yields the zero value of non-bool
+// types, and record dictionaries fill structs through their `odoo` tags.
+func assignValue(src interface{}, dst reflect.Value) error {
+	if dst.Kind() != reflect.Ptr && dst.CanAddr() && dst.Addr().Type().Implements(unmarshalerType) {
+		return dst.Addr().Interface().(Unmarshaler).UnmarshalOdoo(src)
+	}
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if b, ok := src.(bool); ok && !b && dst.Kind() != reflect.Bool && dst.Kind() != reflect.Interface {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dst.Type()) {
+		dst.Set(sv)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		ptr := reflect.New(dst.Type().Elem())
+		if err := assignValue(src, ptr.Elem()); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch n := src.(type) {
+		case int64:
+			dst.SetInt(n)
+			return nil
+		case float64:
+			if n == float64(int64(n)) {
+				dst.SetInt(int64(n))
+				return nil
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		switch n := src.(type) {
+		case int64:
+			dst.SetFloat(float64(n))
+			return nil
+		case float64:
+			dst.SetFloat(n)
+			return nil
+		}
+	case reflect.Slice:
+		items, ok := src.([]interface{})
+		if !ok {
+			break
+		}
+		out := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := assignValue(item, out.Index(i)); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Map:
+		fields, ok := src.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			break
+		}
+		out := reflect.MakeMapWithSize(dst.Type(), len(fields))
+		for k, item := range fields {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := assignValue(item, elem); err != nil {
+				return fmt.Errorf("key %q: %w", k, err)
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Struct:
+		if s, ok := src.(string); ok && dst.Type() == reflect.TypeOf(time.Time{}) {
+			dt, err := ParseDateTime(s)
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(dt.Time))
+			return nil
+		}
+		record, ok := src.(map[string]interface{})
+		if !ok {
+			break
+		}
+		out := reflect.New(dst.Type()).Elem()
+		if err := decodeStruct(record, out); err != nil {
+			return err
+		}
+		dst.Set(out)
+		return nil
+	}
+
+	if sv.Type().ConvertibleTo(dst.Type()) && sv.Kind() == dst.Kind() {
+		dst.Set(sv.Convert(dst.Type()))
+		return nil
+	}
+	return fmt.Errorf("%w: cannot decode %T into %s", ErrInvalidResponse, src, dst.Type())
+}
+
+// toID converts a raw id value (int64 from XML-RPC, float64 from generic JSON, int) to int64.
+func toID(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case float64:
+		if n == float64(int64(n)) {
+			return int64(n), true
+		}
+	}
+	return 0, false
+}
+
+// toIDs converts a raw x2many value (a list of ids) to []int64; `false` yields nil.
+func toIDs(v interface{}) ([]int64, error) {
+	switch list := v.(type) {
+	case nil, bool:
+		return nil, nil
+	case []int64:
+		return list, nil
+	case []interface{}:
+		ids := make([]int64, 0, len(list))
+		for _, item := range list {
+			id, ok := toID(item)
+			if !ok {
+				return nil, fmt.Errorf("unexpected id %v (%T)", item, item)
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+	return nil, fmt.Errorf("unexpected id list %T", v)
+}
+
+// many2oneID extracts the id and display name of a raw many2one value, which Odoo returns
+// as [id, "name"] or `false` when empty. A bare id is also accepted.
+func many2oneID(v interface{}) (int64, string) {
+	switch t := v.(type) {
+	case []interface{}:
+		if len(t) == 0 {
+			return 0, ""
+		}
+		id, _ := toID(t[0])
+		name := ""
+		if len(t) > 1 {
+			name, _ = t[1].(string)
+		}
+		return id, name
+	default:
+		id, _ := toID(v)
+		return id, ""
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/kolo/xmlrpc"
 )
 
 // Common Odoo client specific errors.
@@ -32,6 +34,22 @@ var (
 	// ErrInvalidResponse is returned when the Odoo RPC response is
 	// malformed or not in the expected format.
 	ErrInvalidResponse = errors.New("invalid Odoo RPC response")
+
+	// ErrSessionExpired indica que la sesión web (cookie `session_id`) ya no es válida.
+	// El cliente vuelve a autenticarse automáticamente y reintenta la llamada una vez.
+	ErrSessionExpired = errors.New("godoo: Odoo session expired")
+
+	// ErrConcurrentModification indica que el registro fue modificado por otro usuario
+	// desde que se leyó (su write_date cambió), por lo que la escritura no se realizó.
+	ErrConcurrentModification = errors.New("godoo: record was modified concurrently")
+
+	// ErrReportNotFound indica que no existe ningún ir.actions.report con el XML ID o
+	// report_name indicado.
+	ErrReportNotFound = errors.New("godoo: report not found")
+
+	// ErrReportUnsupported indica que el informe no se puede renderizar con el transporte
+	// o la versión de Odoo del cliente (p. ej. Odoo 14+ sin TransportWebSession).
+	ErrReportUnsupported = errors.New("godoo: report rendering not supported")
 )
 
 // OdooRPCError representa un error más estructurado devuelto por el servidor Odoo XML-RPC.
@@ -55,6 +73,112 @@ func (e *OdooRPCError) Unwrap() error {
 	return e.OriginalError
 }
 
+// ConfigError describe un parámetro de configuración inválido (DSN, variable de entorno
+// o perfil), indicando dónde se encontró para que el error sea accionable.
+type ConfigError struct {
+	Source string // Origen del parámetro, p. ej. "dsn", "env" o "profile staging"
+	Param  string // Nombre del parámetro problemático, p. ej. "timeout" u "ODOO_TIMEOUT"
+	Value  string // Valor recibido (nunca contiene secretos)
+	Err    error  // Causa subyacente
+}
+
+// Error implementa la interfaz error para ConfigError.
+func (e *ConfigError) Error() string {
+	if e.Value != "" {
+		return fmt.Sprintf("godoo: invalid %s parameter %q=%q: %v", e.Source, e.Param, e.Value, e.Err)
+	}
+	return fmt.Sprintf("godoo: invalid %s parameter %q: %v", e.Source, e.Param, e.Err)
+}
+
+// Unwrap permite el uso de errors.Is y errors.As con ConfigError.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConcurrentModificationError describe un conflicto de bloqueo optimista: el write_date
+// actual del registro no coincide con el esperado.
+type ConcurrentModificationError struct {
+	Model    Model  // Modelo del registro
+	ID       int64  // ID del registro
+	Expected string // write_date con el que se leyó el registro
+	Actual   string // write_date actual en Odoo
+}
+
+// Error implementa la interfaz error para ConcurrentModificationError.
+func (e *ConcurrentModificationError) Error() string {
+	return fmt.Sprintf("%s: %s(%d) write_date is %q, expected %q", ErrConcurrentModification, e.Model, e.ID, e.Actual, e.Expected)
+}
+
+// Unwrap permite comprobar el error con errors.Is(err, ErrConcurrentModification).
+func (e *ConcurrentModificationError) Unwrap() error {
+	return ErrConcurrentModification
+}
+
+// ReportError describe un fallo al resolver o renderizar un informe QWeb.
+type ReportError struct {
+	Report string // XML ID o report_name solicitado
+	Err    error  // Causa: ErrReportNotFound, ErrReportUnsupported o el error de la llamada
+}
+
+// Error implementa la interfaz error para ReportError.
+func (e *ReportError) Error() string {
+	return fmt.Sprintf("godoo: report '%s': %v", e.Report, e.Err)
+}
+
+// Unwrap permite el uso de errors.Is y errors.As con ReportError.
+func (e *ReportError) Unwrap() error {
+	return e.Err
+}
+
+// needsReauthentication reporta si un error de llamada indica que la sesión o el secreto
+// ya no son válidos (sesión web expirada, API key rotada o revocada), en cuyo caso el
+// cliente debe volver a autenticarse con el secreto actual y reintentar.
+func needsReauthentication(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrSessionExpired) || errors.Is(err, ErrAuthenticationFailed) {
+		return true
+	}
+	// execute_kw answers a rejected password or API key with an AccessDenied fault.
+	msg := err.Error()
+	return strings.Contains(msg, "AccessDenied") || strings.Contains(msg, "Access Denied")
+}
+
+// xmlrpcFaultWarning es el código de fault con el que /xmlrpc/2 responde a un UserError
+// (ValidationError, MissingError...); los accesos denegados usan 3 y 4, y el resto 1.
+const xmlrpcFaultWarning = 2
+
+// recordErrorNames son las excepciones de Odoo causadas por los datos de los registros
+// de la llamada, y no por la sesión, los permisos o el servidor.
+var recordErrorNames = []string{
+	"odoo.exceptions.UserError",
+	"odoo.exceptions.ValidationError",
+	"odoo.exceptions.MissingError",
+	"odoo.exceptions.RedirectWarning",
+}
+
+// isRecordError reporta si un error de llamada se debe a los registros enviados (una
+// validación o un UserError), de modo que repetir la llamada con menos registros puede
+// aislar al culpable. Los errores de acceso, de red o del servidor no lo son.
+func isRecordError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var fault xmlrpc.FaultError
+	if errors.As(err, &fault) {
+		return fault.Code == xmlrpcFaultWarning
+	}
+	// JSON-RPC and JSON-2 keep the exception name in the message.
+	msg := err.Error()
+	for _, name := range recordErrorNames {
+		if strings.Contains(msg, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // parseOdooRPCError intenta analizar un error genérico del cliente XML-RPC
 // para devolver un error más específico de godoo.
 // Esto es crucial porque la librería 'kolo/xmlrpc' a menudo devuelve errores como simples strings.
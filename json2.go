@@ -0,0 +1,144 @@
+// godoo/json2.go
+package godoo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// json2ModelMethods lists the model-level methods (decorated with @api.model in Odoo)
+// whose first positional argument is NOT a list of record ids.
+var json2ModelMethods = map[string]bool{
+	"search":              true,
+	"search_count":        true,
+	"search_read":         true,
+	"create":              true,
+	"fields_get":          true,
+	"name_search":         true,
+	"name_create":         true,
+	"read_group":          true,
+	"default_get":         true,
+	"check_access_rights": true,
+	"get_views":           true,
+}
+
+// json2PositionalParams maps the positional arguments that godoo passes to well-known
+// methods (after the ids, for record methods) onto the parameter names the JSON-2 API expects.
+var json2PositionalParams = map[string][]string{
+	"search":              {"domain"},
+	"search_count":        {"domain"},
+	"search_read":         {"domain", "fields"},
+	"create":              {"vals_list"},
+	"fields_get":          {"allfields", "attributes"},
+	"name_search":         {"name"},
+	"name_create":         {"name"},
+	"read_group":          {"domain", "fields", "groupby"},
+	"default_get":         {"fields_list"},
+	"check_access_rights": {"operation"},
+	"read":                {"fields"},
+	"write":               {"vals"},
+	"copy":                {"default"},
+}
+
+// json2Caller implements rpcCaller on top of the Odoo 17+ JSON-2 API:
+// POST <url>/json/2/<model>/<method> with a JSON object of named parameters.
+type json2Caller struct {
+	httpClient *http.Client
+	baseURL    string
+	db         string
+	token      string
+}
+
+// json2Error is the error body returned by the JSON-2 endpoints.
+type json2Error struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Debug   string `json:"debug"`
+}
+
+// json2Body converts execute_kw style positional args into the named parameters of the JSON-2 API.
+func json2Body(method string, args []interface{}, kwargs map[string]interface{}) (map[string]interface{}, error) {
+	body := make(map[string]interface{}, len(kwargs)+2)
+	for k, v := range kwargs {
+		body[k] = v
+	}
+
+	rest := args
+	if !json2ModelMethods[method] && len(rest) > 0 {
+		body["ids"] = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return body, nil
+	}
+
+	names, ok := json2PositionalParams[method]
+	if !ok || len(rest) > len(names) {
+		return nil, fmt.Errorf("godoo: %d positional argument(s) for method '%s' cannot be mapped onto JSON-2 parameters; pass them as keyword arguments", len(rest), method)
+	}
+	for i, v := range rest {
+		if _, dup := body[names[i]]; dup {
+			return nil, fmt.Errorf("godoo: parameter '%s' of method '%s' given both positionally and as keyword argument", names[i], method)
+		}
+		body[names[i]] = v
+	}
+	return body, nil
+}
+
+func (j *json2Caller) call(ctx context.Context, model, method string, args []interface{}, kwargs map[string]interface{}, reply interface{}) error {
+	body, err := json2Body(method, args, kwargs)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("godoo: failed to encode JSON-2 request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/json/2/%s/%s", strings.TrimRight(j.baseURL, "/"), url.PathEscape(model), url.PathEscape(method))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "bearer "+j.token)
+	if j.db != "" {
+		req.Header.Set("X-Odoo-Database", j.db)
+	}
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		var jerr json2Error
+		if json.Unmarshal(raw, &jerr) != nil || jerr.Message == "" {
+			jerr.Message = strings.TrimSpace(string(raw))
+		}
+		if resp.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("%w: %s", ErrAuthenticationFailed, jerr.Message)
+		}
+		return fmt.Errorf("HTTP %d %s: %s", resp.StatusCode, jerr.Name, jerr.Message)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	var result interface{}
+	if err := dec.Decode(&result); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return assignReply(normalizeJSON(result), reply)
+}
+
+// close has nothing to release: the http.Client may be shared with other clients,
+// so its idle connections are only closed by OdooClient.Close.
+func (j *json2Caller) close() {}
@@ -0,0 +1,196 @@
+// godoo/jsonrpc.go
+package godoo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// jsonRPCRequestID numbers outgoing JSON-RPC requests; Odoo echoes it back but does not rely on it.
+var jsonRPCRequestID int64
+
+// jsonRPCRequest is the JSON-RPC 2.0 envelope Odoo's `type="json"` routes expect.
+type jsonRPCRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	ID      int64       `json:"id"`
+}
+
+// jsonRPCResponse is the JSON-RPC 2.0 envelope returned by Odoo.
+type jsonRPCResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *jsonRPCError   `json:"error"`
+}
+
+// jsonRPCError is the `error` member of a failed Odoo JSON-RPC response.
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+		Debug   string `json:"debug"`
+	} `json:"data"`
+}
+
+// jsonRPCSessionExpired is the exception name Odoo reports when the web session cookie is no longer valid.
+const jsonRPCSessionExpired = "odoo.http.SessionExpiredException"
+
+// err converts the JSON-RPC error into a Go error. The exception name is kept in
+// the message so parseOdooRPCError can still classify it.
+func (e *jsonRPCError) err() error {
+	msg := e.Data.Message
+	if msg == "" {
+		msg = e.Message
+	}
+	switch e.Data.Name {
+	case jsonRPCSessionExpired:
+		return fmt.Errorf("%w: %s", ErrSessionExpired, msg)
+	case "odoo.exceptions.AccessDenied":
+		return fmt.Errorf("%w: %s", ErrAuthenticationFailed, msg)
+	}
+	if e.Data.Name != "" {
+		return fmt.Errorf("%s: %s", e.Data.Name, msg)
+	}
+	return fmt.Errorf("JSON-RPC error %d: %s", e.Code, msg)
+}
+
+// postJSONRPC sends `params` to an Odoo JSON-RPC route and decodes its `result` into reply
+// (which may be nil when the result is not needed).
+func postJSONRPC(ctx context.Context, httpClient *http.Client, endpoint string, params interface{}, reply interface{}) error {
+	payload, err := json.Marshal(jsonRPCRequest{
+		JSONRPC: "2.0",
+		Method:  "call",
+		Params:  params,
+		ID:      atomic.AddInt64(&jsonRPCRequestID, 1),
+	})
+	if err != nil {
+		return fmt.Errorf("godoo: failed to encode JSON-RPC request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("HTTP %d from %s: %s", resp.StatusCode, endpoint, bytes.TrimSpace(raw))
+	}
+
+	var envelope jsonRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if envelope.Error != nil {
+		return envelope.Error.err()
+	}
+	if reply == nil {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(envelope.Result))
+	dec.UseNumber()
+	var result interface{}
+	if err := dec.Decode(&result); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return assignReply(normalizeJSON(result), reply)
+}
+
+// jsonrpcCaller implements rpcCaller through Odoo's /jsonrpc endpoint, calling
+// `execute_kw` on the `object` service with the same arguments as XML-RPC.
+type jsonrpcCaller struct {
+	httpClient *http.Client
+	endpoint   string
+	db         string
+	uid        int64
+	secret     string
+}
+
+func (j *jsonrpcCaller) call(ctx context.Context, model, method string, args []interface{}, kwargs map[string]interface{}, reply interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+	if kwargs == nil {
+		kwargs = map[string]interface{}{}
+	}
+	params := map[string]interface{}{
+		"service": "object",
+		"method":  "execute_kw",
+		"args":    []interface{}{j.db, j.uid, j.secret, model, method, args, kwargs},
+	}
+	return postJSONRPC(ctx, j.httpClient, j.endpoint, params, reply)
+}
+
+// close has nothing to release: the http.Client may be shared with other clients,
+// so its idle connections are only closed by OdooClient.Close.
+func (j *jsonrpcCaller) close() {}
+
+// authenticateJSONRPC resolves the uid through the `common.login` service (unless it is
+// already known) and installs a jsonrpcCaller. Called by authenticate with c.sess.mu held.
+func (c *OdooClient) authenticateJSONRPC(ctx context.Context, secret string) error {
+	endpoint := c.url + "/jsonrpc"
+
+	uid := c.knownUID
+	if uid == 0 {
+		params := map[string]interface{}{
+			"service": "common",
+			"method":  "login",
+			"args":    []interface{}{c.db, c.username, secret},
+		}
+		var result interface{}
+		if err := postJSONRPC(ctx, c.httpClient, endpoint, params, &result); err != nil {
+			c.logger.Error("Odoo authentication failed",
+				zap.Error(err),
+				zap.String("url", endpoint),
+				zap.String("db", c.db),
+				zap.String("username", c.username),
+				zap.String("op", "authenticate"),
+			)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("%w: %s", ErrAuthenticationFailed, err.Error())
+		}
+		// `login` answers `false` for wrong credentials.
+		id, ok := result.(int64)
+		if !ok || id <= 0 {
+			return fmt.Errorf("%w: invalid %s for user '%s' on database '%s'", ErrAuthenticationFailed, c.credentialType, c.username, c.db)
+		}
+		uid = id
+	}
+
+	c.sess.uid = uid
+	c.sess.caller = &jsonrpcCaller{
+		httpClient: c.httpClient,
+		endpoint:   endpoint,
+		db:         c.db,
+		uid:        uid,
+		secret:     secret,
+	}
+	c.sess.lastAuth = time.Now()
+	c.logger.Info("Successfully authenticated with Odoo",
+		zap.Int64("uid", c.sess.uid),
+		zap.String("db", c.db),
+		zap.String("transport", string(TransportJSONRPC)),
+		zap.String("op", "authenticate"),
+	)
+	return nil
+}
@@ -0,0 +1,423 @@
+// godoo/manager.go
+package godoo
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrManagerClosed es devuelto por Manager.Get después de Manager.Close.
+var ErrManagerClosed = errors.New("godoo: client manager closed")
+
+// TenantConfig describes how to reach the Odoo database of one tenant.
+type TenantConfig struct {
+	URL      string
+	DB       string
+	Username string
+
+	// Credentials supplies the secret; CredentialType says how it is used
+	// (defaults to CredentialPassword).
+	Credentials    CredentialProvider
+	CredentialType CredentialType
+
+	// SkipTLSVerify disables certificate verification for this tenant's host. The clients
+	// that skip verification share a transport separate from the verifying ones.
+	// ADVERTENCIA: No usar en producción.
+	SkipTLSVerify bool
+
+	// Options are applied to the tenant's client after the manager's WithClientOptions.
+	Options []Option
+}
+
+// TenantResolver maps a tenant key (customer id, subdomain...) to its Odoo connection.
+// It is consulted the first time a tenant is requested and again after its client has
+// been evicted, so implementations may read from a database or a secret store.
+type TenantResolver interface {
+	Resolve(ctx context.Context, tenantKey string) (*TenantConfig, error)
+}
+
+// TenantResolverFunc adapts a function to the TenantResolver interface.
+type TenantResolverFunc func(ctx context.Context, tenantKey string) (*TenantConfig, error)
+
+// Resolve calls f(ctx, tenantKey).
+func (f TenantResolverFunc) Resolve(ctx context.Context, tenantKey string) (*TenantConfig, error) {
+	return f(ctx, tenantKey)
+}
+
+// ManagerOption es una función que configura un Manager.
+type ManagerOption func(*Manager)
+
+// WithIdleTimeout establece tras cuánto tiempo sin uso se descarta el cliente de un tenant.
+// Un valor <= 0 deshabilita la expulsión por inactividad.
+func WithIdleTimeout(d time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.idleTimeout = d
+	}
+}
+
+// WithHealthCheckInterval establece cada cuánto Get verifica (Ping) un cliente en caché
+// antes de devolverlo. Un valor <= 0 sólo verifica al crear el cliente.
+func WithHealthCheckInterval(d time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.healthInterval = d
+	}
+}
+
+// WithManagerLogger establece el logger de Zap usado por el Manager.
+func WithManagerLogger(logger *zap.Logger) ManagerOption {
+	return func(m *Manager) {
+		m.logger = logger
+	}
+}
+
+// WithClientOptions establece Options aplicadas a todos los clientes creados por el Manager.
+func WithClientOptions(opts ...Option) ManagerOption {
+	return func(m *Manager) {
+		m.clientOptions = append(m.clientOptions, opts...)
+	}
+}
+
+// managerKey identifies a cached client: one per (url, db, user) and credential, so a
+// tenant whose key was rotated or whose credential type changed gets a new client.
+type managerKey struct {
+	url, db, user string
+	credential    string // fingerprint of the credential type and secret, see credentialFingerprint
+}
+
+// credentialFingerprint hashes the credential type and secret of cfg, so the key of a
+// cached client does not hold the secret itself.
+func credentialFingerprint(ctx context.Context, cfg *TenantConfig) (string, error) {
+	kind := cfg.CredentialType
+	if kind == "" {
+		kind = CredentialPassword
+	}
+	var secret string
+	if cfg.Credentials != nil {
+		var err error
+		if secret, err = cfg.Credentials.Secret(ctx); err != nil {
+			return "", err
+		}
+	}
+	sum := sha256.Sum256([]byte(string(kind) + "\x00" + secret))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// managedClient is a cached client together with its bookkeeping.
+type managedClient struct {
+	client      *OdooClient
+	lastUsed    time.Time
+	lastChecked time.Time
+	tenants     map[string]struct{}
+}
+
+// Manager lazily creates, caches and health-checks one OdooClient per (url, db, user) and
+// credential for multi-tenant services. Clients of the same host and TLS setting share one
+// HTTP transport (and thus its connection pool), and clients unused for the idle timeout
+// are evicted by a background janitor. A Manager is safe for concurrent use; call Close when done.
+type Manager struct {
+	resolver       TenantResolver
+	idleTimeout    time.Duration
+	healthInterval time.Duration
+	clientOptions  []Option
+	logger         *zap.Logger
+
+	mu         sync.Mutex
+	clients    map[managerKey]*managedClient
+	tenants    map[string]managerKey
+	transports map[string]*http.Transport
+	closed     bool
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewManager creates a Manager that resolves tenants with resolver. By default idle clients
+// are evicted after 30 minutes and cached clients are health-checked every 5 minutes.
+func NewManager(resolver TenantResolver, opts ...ManagerOption) *Manager {
+	m := &Manager{
+		resolver:       resolver,
+		idleTimeout:    30 * time.Minute,
+		healthInterval: 5 * time.Minute,
+		logger:         createLogger(EnvProduction),
+		clients:        map[managerKey]*managedClient{},
+		tenants:        map[string]managerKey{},
+		transports:     map[string]*http.Transport{},
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.idleTimeout > 0 {
+		go m.janitor()
+	} else {
+		close(m.done)
+	}
+	return m
+}
+
+// Get returns the client of tenantKey, resolving and creating it on first use. A cached
+// client whose last health check is older than the health-check interval is pinged first;
+// if that fails it is replaced by a freshly resolved one.
+func (m *Manager) Get(ctx context.Context, tenantKey string) (*OdooClient, error) {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, ErrManagerClosed
+	}
+	key, ok := m.tenants[tenantKey]
+	entry := m.clients[key]
+	if !ok || entry == nil {
+		m.mu.Unlock()
+		return m.create(ctx, tenantKey)
+	}
+	entry.lastUsed = time.Now()
+	needsCheck := m.healthInterval > 0 && time.Since(entry.lastChecked) >= m.healthInterval
+	m.mu.Unlock()
+
+	if !needsCheck {
+		return entry.client, nil
+	}
+	err := entry.client.Ping(ctx)
+	if err == nil {
+		m.mu.Lock()
+		entry.lastChecked = time.Now()
+		m.mu.Unlock()
+		return entry.client, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.logger.Warn("Cached Odoo client failed health check, recreating",
+		zap.String("tenant", tenantKey),
+		zap.String("db", key.db),
+		zap.Error(err),
+		zap.String("op", "Manager.Get"),
+	)
+	m.evict(key, entry)
+	return m.create(ctx, tenantKey)
+}
+
+// create resolves tenantKey and returns the cached client for its (url, db, user) and
+// credential, creating and pinging a new one when none exists.
+func (m *Manager) create(ctx context.Context, tenantKey string) (*OdooClient, error) {
+	cfg, err := m.resolver.Resolve(ctx, tenantKey)
+	if err != nil {
+		return nil, fmt.Errorf("godoo: failed to resolve tenant %q: %w", tenantKey, err)
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("godoo: resolver returned no configuration for tenant %q", tenantKey)
+	}
+	fingerprint, err := credentialFingerprint(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("godoo: failed to read the credentials of tenant %q: %w", tenantKey, err)
+	}
+	key := managerKey{url: strings.TrimRight(cfg.URL, "/"), db: cfg.DB, user: cfg.Username, credential: fingerprint}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, ErrManagerClosed
+	}
+	// Another tenant key (or a concurrent Get) may already have created this client.
+	if entry, ok := m.clients[key]; ok {
+		entry.lastUsed = time.Now()
+		entry.tenants[tenantKey] = struct{}{}
+		m.tenants[tenantKey] = key
+		m.mu.Unlock()
+		return entry.client, nil
+	}
+
+	transport, err := m.transportFor(cfg)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	kind := cfg.CredentialType
+	if kind == "" {
+		kind = CredentialPassword
+	}
+	opts := []Option{WithLogger(m.logger)}
+	opts = append(opts, m.clientOptions...)
+	opts = append(opts,
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithCredentialProvider(kind, cfg.Credentials),
+	)
+	opts = append(opts, cfg.Options...)
+
+	client, err := New(cfg.URL, cfg.DB, cfg.Username, "", opts...)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("godoo: failed to create client for tenant %q: %w", tenantKey, err)
+	}
+	entry := &managedClient{
+		client:   client,
+		lastUsed: time.Now(),
+		tenants:  map[string]struct{}{tenantKey: {}},
+	}
+	m.clients[key] = entry
+	m.tenants[tenantKey] = key
+	m.mu.Unlock()
+
+	// Authenticate eagerly so bad tenant credentials surface here rather than on first use.
+	if err := client.Ping(ctx); err != nil {
+		m.evict(key, entry)
+		return nil, err
+	}
+	m.mu.Lock()
+	entry.lastChecked = time.Now()
+	m.mu.Unlock()
+
+	m.logger.Info("Created Odoo client for tenant",
+		zap.String("tenant", tenantKey),
+		zap.String("url", cfg.URL),
+		zap.String("db", cfg.DB),
+		zap.String("op", "Manager.Get"),
+	)
+	return client, nil
+}
+
+// transportFor returns the HTTP transport shared by every client of cfg's host.
+// Called with m.mu held.
+func (m *Manager) transportFor(cfg *TenantConfig) (*http.Transport, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Odoo URL: %w", err)
+	}
+	hostKey := u.Scheme + "://" + u.Host
+	if cfg.SkipTLSVerify {
+		hostKey += "#insecure"
+	}
+	if tr, ok := m.transports[hostKey]; ok {
+		return tr, nil
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.SkipTLSVerify {
+		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	m.transports[hostKey] = tr
+	return tr, nil
+}
+
+// evict removes entry (if it is still the cached client for key) and drops its session.
+// The client is not closed: that would close the idle connections of the transport its
+// host shares with other tenants.
+func (m *Manager) evict(key managerKey, entry *managedClient) {
+	m.mu.Lock()
+	if current, ok := m.clients[key]; ok && current == entry {
+		delete(m.clients, key)
+		for tenant := range entry.tenants {
+			if m.tenants[tenant] == key {
+				delete(m.tenants, tenant)
+			}
+		}
+	}
+	m.mu.Unlock()
+	entry.client.invalidate()
+}
+
+// Remove evicts the client of tenantKey, e.g. after its credentials were revoked.
+// Other tenant keys sharing the same client are evicted too.
+func (m *Manager) Remove(tenantKey string) {
+	m.mu.Lock()
+	key, ok := m.tenants[tenantKey]
+	entry := m.clients[key]
+	m.mu.Unlock()
+	if ok && entry != nil {
+		m.evict(key, entry)
+	}
+}
+
+// Len returns the number of cached clients.
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.clients)
+}
+
+// janitor periodically evicts clients idle for longer than the idle timeout.
+func (m *Manager) janitor() {
+	defer close(m.done)
+
+	interval := m.idleTimeout / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.evictIdle()
+		}
+	}
+}
+
+// evictIdle closes every client not used within the idle timeout.
+func (m *Manager) evictIdle() {
+	type victim struct {
+		key   managerKey
+		entry *managedClient
+	}
+	var idle []victim
+
+	m.mu.Lock()
+	for key, entry := range m.clients {
+		if time.Since(entry.lastUsed) >= m.idleTimeout {
+			idle = append(idle, victim{key, entry})
+		}
+	}
+	m.mu.Unlock()
+
+	for _, v := range idle {
+		m.logger.Debug("Evicting idle Odoo client",
+			zap.String("url", v.key.url),
+			zap.String("db", v.key.db),
+			zap.String("op", "Manager.evictIdle"),
+		)
+		m.evict(v.key, v.entry)
+	}
+}
+
+// Close stops the janitor and closes every cached client and shared transport.
+// Get returns ErrManagerClosed afterwards.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	clients := m.clients
+	transports := m.transports
+	m.clients = map[managerKey]*managedClient{}
+	m.tenants = map[string]managerKey{}
+	m.transports = map[string]*http.Transport{}
+	m.mu.Unlock()
+
+	close(m.stop)
+	<-m.done
+
+	for _, entry := range clients {
+		entry.client.Close()
+	}
+	for _, tr := range transports {
+		tr.CloseIdleConnections()
+	}
+}
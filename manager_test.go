@@ -0,0 +1,153 @@
+// godoo/manager_test.go
+package godoo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// tenantTable is a TenantResolver backed by a map, safe to change between Gets.
+type tenantTable struct {
+	mu      sync.Mutex
+	tenants map[string]TenantConfig
+}
+
+func (tt *tenantTable) Resolve(ctx context.Context, key string) (*TenantConfig, error) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	cfg, ok := tt.tenants[key]
+	if !ok {
+		return nil, errors.New("unknown tenant")
+	}
+	return &cfg, nil
+}
+
+func (tt *tenantTable) set(key string, cfg TenantConfig) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.tenants[key] = cfg
+}
+
+func newTestManager(t *testing.T, tenants *tenantTable, opts ...ManagerOption) *Manager {
+	t.Helper()
+	m := NewManager(tenants, append([]ManagerOption{WithManagerLogger(zap.NewNop()), WithIdleTimeout(0)}, opts...)...)
+	t.Cleanup(m.Close)
+	return m
+}
+
+func clientTransport(t *testing.T, c *OdooClient) http.RoundTripper {
+	t.Helper()
+	if c.httpClient == nil || c.httpClient.Transport == nil {
+		t.Fatal("client has no HTTP transport")
+	}
+	return c.httpClient.Transport
+}
+
+func TestManagerSharesTransportsPerHost(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	other := newFakeOdoo(t, "s3cret")
+	secret := StaticCredential("s3cret")
+	tenants := &tenantTable{tenants: map[string]TenantConfig{
+		"acme":     {URL: srv.URL, DB: "test", Username: "admin", Credentials: secret},
+		"globex":   {URL: srv.URL + "/", DB: "test", Username: "bob", Credentials: secret},
+		"alias":    {URL: srv.URL, DB: "test", Username: "admin", Credentials: secret},
+		"insecure": {URL: srv.URL, DB: "test", Username: "eve", Credentials: secret, SkipTLSVerify: true},
+		"initech":  {URL: other.URL, DB: "test", Username: "admin", Credentials: secret},
+	}}
+	m := newTestManager(t, tenants)
+	ctx := context.Background()
+
+	get := func(key string) *OdooClient {
+		t.Helper()
+		c, err := m.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", key, err)
+		}
+		return c
+	}
+	acme, globex, alias, insecure, initech := get("acme"), get("globex"), get("alias"), get("insecure"), get("initech")
+
+	if acme == globex {
+		t.Error("tenants of different users share a client")
+	}
+	if acme != alias {
+		t.Error("tenant keys of the same (url, db, user) and credential got different clients")
+	}
+	if clientTransport(t, acme) != clientTransport(t, globex) {
+		t.Error("clients of the same host do not share their transport")
+	}
+	if clientTransport(t, acme) == clientTransport(t, insecure) {
+		t.Error("a client skipping TLS verification shares the verifying clients' transport")
+	}
+	if clientTransport(t, acme) == clientTransport(t, initech) {
+		t.Error("clients of different hosts share their transport")
+	}
+	if got := get("acme"); got != acme {
+		t.Error("Get did not return the cached client")
+	}
+	if n := m.Len(); n != 4 {
+		t.Errorf("Len() = %d, want 4", n)
+	}
+}
+
+func TestManagerRecreatesClientAfterRotation(t *testing.T) {
+	srv := newFakeOdoo(t, "old")
+	tenants := &tenantTable{tenants: map[string]TenantConfig{
+		"acme": {URL: srv.URL, DB: "test", Username: "admin", Credentials: StaticCredential("old"), CredentialType: CredentialAPIKey},
+	}}
+	m := newTestManager(t, tenants, WithHealthCheckInterval(time.Nanosecond))
+	ctx := context.Background()
+
+	first, err := m.Get(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	// The key is rotated: the cached client fails its health check and the tenant is
+	// resolved again, getting a client for the new credential.
+	srv.setSecret("new")
+	tenants.set("acme", TenantConfig{URL: srv.URL, DB: "test", Username: "admin", Credentials: StaticCredential("new"), CredentialType: CredentialAPIKey})
+	second, err := m.Get(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Get after rotation: %v", err)
+	}
+	if second == first {
+		t.Error("Get returned the client of the revoked credential")
+	}
+	if clientTransport(t, second) != clientTransport(t, first) {
+		t.Error("the new client does not reuse the host's transport")
+	}
+	if n := m.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1", n)
+	}
+}
+
+func TestManagerRejectsBadCredentialsAndClose(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	tenants := &tenantTable{tenants: map[string]TenantConfig{
+		"acme": {URL: srv.URL, DB: "test", Username: "admin", Credentials: StaticCredential("wrong")},
+	}}
+	m := newTestManager(t, tenants)
+	ctx := context.Background()
+
+	if _, err := m.Get(ctx, "acme"); !errors.Is(err, ErrAuthenticationFailed) {
+		t.Errorf("Get with a wrong password: error = %v, want ErrAuthenticationFailed", err)
+	}
+	if n := m.Len(); n != 0 {
+		t.Errorf("Len() = %d after a failed Get, want 0", n)
+	}
+	if _, err := m.Get(ctx, "unknown"); err == nil {
+		t.Error("Get of an unknown tenant succeeded")
+	}
+
+	m.Close()
+	if _, err := m.Get(ctx, "acme"); !errors.Is(err, ErrManagerClosed) {
+		t.Errorf("Get after Close: error = %v, want ErrManagerClosed", err)
+	}
+}
@@ -4,11 +4,17 @@ package godoo
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"go.uber.org/zap"
 )
 
 // CallMethod calls a custom method on the specified Odoo model.
+// The provided `args` are spread into execute_kw after the method name, as they always
+// were: the first one is the list of positional arguments of the Odoo method and an
+// optional second one (a map) its keyword arguments, e.g.
+//
+//	client.CallMethod(ctx, "res.partner", "check_access_rights", []interface{}{"read", false})
 func (c *OdooClient) CallMethod(ctx context.Context, model, method string, args ...interface{}) (interface{}, error) { // Add context
 	c.logger.Debug("Performing Odoo custom method call",
 		zap.String("model", model),
@@ -17,38 +23,13 @@ func (c *OdooClient) CallMethod(ctx context.Context, model, method string, args
 		zap.String("op", "CallMethod"),
 	)
 
-	uid, rpcClient, err := c.getConnection(ctx) // Pass context
+	positional, kwargs, err := callMethodArgs(args)
 	if err != nil {
-		c.logger.Error("Failed to get Odoo connection for custom method call",
-			zap.Error(err),
-			zap.String("model", model),
-			zap.String("method", method),
-			zap.String("op", "CallMethod"),
-		)
-		return nil, err
+		return nil, fmt.Errorf("failed to call method '%s' on model '%s': %w", method, model, err)
 	}
-
-	params := []interface{}{c.db, uid, c.password, model, method}
-	params = append(params, args...) // Append the actual arguments for the Odoo method
-
 	var result interface{}
-	// The `execute_kw` method requires a final map for keyword arguments (kwargs).
-	// Since CallMethod allows flexible `args...`, we append an empty map if no kwargs are provided.
-	// If the last arg is a map, it's assumed to be kwargs for execute_kw.
-	// For simplicity here, we assume the provided `args` are directly for the Odoo method,
-	// and execute_kw's final kwargs parameter is an empty map unless explicitly passed.
-	// More sophisticated handling could check if the last `arg` is `map[string]interface{}`
-	// and use it as the kwargs for execute_kw. For now, matching previous behavior.
-	err = rpcClient.Call("execute_kw", append(params, map[string]interface{}{}), &result)
-	if err != nil {
-		c.logger.Error("Failed to execute Odoo custom method",
-			zap.Error(err),
-			zap.String("model", model),
-			zap.String("method", method),
-			zap.Any("args", args),
-			zap.String("op", "CallMethod"),
-		)
-		return nil, fmt.Errorf("failed to call method '%s' on model '%s': %w", method, model, err)
+	if err := c.executeRPC(ctx, model, method, positional, kwargs, &result); err != nil {
+		return nil, err
 	}
 
 	c.logger.Info("Odoo custom method call completed successfully",
@@ -58,3 +39,31 @@ func (c *OdooClient) CallMethod(ctx context.Context, model, method string, args
 	)
 	return result, nil
 }
+
+// callMethodArgs splits the execute_kw arguments of CallMethod into the method's positional
+// arguments and keyword arguments.
+func callMethodArgs(args []interface{}) ([]interface{}, map[string]interface{}, error) {
+	if len(args) > 2 {
+		return nil, nil, fmt.Errorf("godoo: execute_kw takes a list of arguments and a map of keyword arguments, got %d values", len(args))
+	}
+	positional := []interface{}{}
+	if len(args) > 0 && args[0] != nil {
+		v := reflect.ValueOf(args[0])
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, nil, fmt.Errorf("godoo: the method arguments must be a list, got %T", args[0])
+		}
+		positional = make([]interface{}, v.Len())
+		for i := range positional {
+			positional[i] = v.Index(i).Interface()
+		}
+	}
+	var kwargs map[string]interface{}
+	if len(args) > 1 && args[1] != nil {
+		kw, ok := args[1].(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("godoo: the method keyword arguments must be a map, got %T", args[1])
+		}
+		kwargs = kw
+	}
+	return positional, kwargs, nil
+}
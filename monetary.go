@@ -0,0 +1,238 @@
+// godoo/monetary.go
+package godoo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// Currency holds the rounding rules of a res.currency record.
+type Currency struct {
+	ID            int64
+	Name          string  // ISO code, e.g. "USD"
+	Symbol        string  // e.g. "$"
+	Rounding      float64 // Smallest representable amount, e.g. 0.01
+	DecimalPlaces int     // Digits after the decimal point derived from Rounding
+}
+
+// Round rounds amount to the currency's rounding, half-up, as Odoo's float_round does.
+func (cur *Currency) Round(amount float64) float64 {
+	return roundFloat(amount, cur.Rounding, cur.DecimalPlaces)
+}
+
+// IsZero reports whether amount rounds to zero in the currency.
+func (cur *Currency) IsZero(amount float64) bool {
+	return cur.Round(amount) == 0
+}
+
+// Compare compares two amounts after rounding them to the currency, returning -1, 0 or 1
+// like Odoo's float_compare. Use it instead of == when reconciling amounts.
+func (cur *Currency) Compare(a, b float64) int {
+	diff := cur.Round(a - b)
+	switch {
+	case diff < 0:
+		return -1
+	case diff > 0:
+		return 1
+	}
+	return 0
+}
+
+// RoundDecimal rounds value to digits decimal places, half-up, e.g. with the digits of a
+// decimal.precision (see DecimalPrecision) or of FieldSchema.Digits.
+func RoundDecimal(value float64, digits int) float64 {
+	return roundFloat(value, math.Pow10(-digits), digits)
+}
+
+// roundFloat is Odoo's float_round with HALF-UP: value is rounded to a multiple of
+// rounding, nudged by an epsilon so that representation errors (2.675 stored as
+// 2.67499999...) do not round the wrong way, and cleaned to digits decimals.
+func roundFloat(value, rounding float64, digits int) float64 {
+	if rounding <= 0 || value == 0 {
+		return value
+	}
+	normalized := value / rounding
+	epsilon := math.Pow(2, math.Log2(math.Abs(normalized))-52)
+	normalized += math.Copysign(epsilon, normalized)
+	rounded := math.Round(normalized) * rounding
+	if digits < 0 {
+		digits = 0
+	}
+	clean, err := strconv.ParseFloat(strconv.FormatFloat(rounded, 'f', digits, 64), 64)
+	if err != nil {
+		return rounded
+	}
+	return clean
+}
+
+// Monetary is the value of a monetary field together with its currency. When decoding a
+// struct, the currency comes from the record's currency field: `currency_id` by default,
+// or the one named with the `currency` tag option, e.g.
+//
+//	Total godoo.Monetary `odoo:"amount_total,readonly,currency=currency_id"`
+//
+// The currency field is read automatically by Repository. Monetary is written as the
+// bare amount.
+type Monetary struct {
+	Amount   float64
+	Currency Many2One
+}
+
+// UnmarshalOdoo decodes the amount (`false` as 0). The currency is set by the struct decoder.
+func (m *Monetary) UnmarshalOdoo(value interface{}) error {
+	switch v := value.(type) {
+	case nil, bool:
+		m.Amount = 0
+	case float64:
+		m.Amount = v
+	default:
+		// Integers arrive as int64 (XML-RPC <i8>), int32 (<int>) or int, as for NullFloat.
+		i, ok := toID(v)
+		if !ok {
+			return fmt.Errorf("%w: cannot decode %T into Monetary", ErrInvalidResponse, value)
+		}
+		m.Amount = float64(i)
+	}
+	return nil
+}
+
+// MarshalOdoo encodes the amount.
+func (m Monetary) MarshalOdoo() (interface{}, error) {
+	return m.Amount, nil
+}
+
+// Round returns m rounded to cur, which should be m's currency.
+func (m Monetary) Round(cur *Currency) Monetary {
+	m.Amount = cur.Round(m.Amount)
+	return m
+}
+
+// Currency returns the rounding rules of the res.currency record id. Currencies are read
+// once per session and cached.
+func (c *OdooClient) Currency(ctx context.Context, id int64) (*Currency, error) {
+	currencies, err := c.Currencies(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cur, ok := currencies[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: for model '%s' with ID %v", ErrRecordNotFound, ModelResCurrency, id)
+	}
+	return cur, nil
+}
+
+// Currencies returns the rounding rules of the given res.currency records, keyed by id,
+// reading the ones not cached yet in a single call.
+func (c *OdooClient) Currencies(ctx context.Context, ids ...int64) (map[int64]*Currency, error) {
+	result := make(map[int64]*Currency, len(ids))
+	var missing []int64
+	c.sess.mu.Lock()
+	for _, id := range ids {
+		if cur, ok := c.sess.currencies[id]; ok {
+			result[id] = cur
+		} else if id != 0 {
+			missing = append(missing, id)
+		}
+	}
+	c.sess.mu.Unlock()
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	// Archived currencies still round amounts of old documents.
+	records, err := c.Read(ctx, ModelResCurrency, missing, Fields{"name", "symbol", "rounding", "decimal_places"}, ActiveTest(false))
+	if err != nil {
+		return nil, err
+	}
+
+	c.sess.mu.Lock()
+	defer c.sess.mu.Unlock()
+	if c.sess.currencies == nil {
+		c.sess.currencies = map[int64]*Currency{}
+	}
+	for _, r := range records {
+		cur := &Currency{}
+		cur.ID, _ = toID(r["id"])
+		cur.Name, _ = r["name"].(string)
+		cur.Symbol, _ = r["symbol"].(string)
+		cur.Rounding, _ = GetFloat(r, "rounding")
+		if places, ok := toID(r["decimal_places"]); ok {
+			cur.DecimalPlaces = int(places)
+		} else if cur.Rounding > 0 {
+			cur.DecimalPlaces = int(math.Ceil(math.Log10(1 / cur.Rounding)))
+		}
+		c.sess.currencies[cur.ID] = cur
+		result[cur.ID] = cur
+	}
+	return result, nil
+}
+
+// RoundMonetary rounds m using the rules of its currency.
+func (c *OdooClient) RoundMonetary(ctx context.Context, m Monetary) (Monetary, error) {
+	if m.Currency.ID == 0 {
+		return m, fmt.Errorf("godoo: cannot round an amount without currency")
+	}
+	cur, err := c.Currency(ctx, m.Currency.ID)
+	if err != nil {
+		return m, err
+	}
+	return m.Round(cur), nil
+}
+
+// DecimalPrecision returns the digits of a decimal.precision usage (e.g. "Product Price",
+// "Product Unit of Measure", "Discount"), cached per session.
+func (c *OdooClient) DecimalPrecision(ctx context.Context, name string) (int, error) {
+	c.sess.mu.Lock()
+	digits, ok := c.sess.precisions[name]
+	c.sess.mu.Unlock()
+	if ok {
+		return digits, nil
+	}
+
+	id, err := c.SearchOne(ctx, "decimal.precision", Domain{{"name", "=", name}})
+	if err != nil {
+		return 0, err
+	}
+	record, err := c.ReadOne(ctx, "decimal.precision", id, Fields{"digits"})
+	if err != nil {
+		return 0, err
+	}
+	value, ok := toID(record["digits"])
+	if !ok {
+		return 0, fmt.Errorf("%w: decimal.precision '%s' has no digits", ErrInvalidResponse, name)
+	}
+
+	c.sess.mu.Lock()
+	if c.sess.precisions == nil {
+		c.sess.precisions = map[string]int{}
+	}
+	c.sess.precisions[name] = int(value)
+	c.sess.mu.Unlock()
+	c.logger.Debug("Read Odoo decimal precision",
+		zap.String("name", name),
+		zap.Int64("digits", value),
+		zap.String("op", "DecimalPrecision"),
+	)
+	return int(value), nil
+}
+
+// FieldDigits returns the number of decimals of a float field, from its digits in the model
+// schema (which reflect its decimal.precision). ok is false for unrestricted floats.
+func (c *OdooClient) FieldDigits(ctx context.Context, model Model, field string) (digits int, ok bool, err error) {
+	schema, err := c.Fields(ctx, model)
+	if err != nil {
+		return 0, false, err
+	}
+	f := schema.Field(field)
+	if f == nil {
+		return 0, false, fmt.Errorf("godoo: model '%s' has no field '%s'", model, field)
+	}
+	if len(f.Digits) != 2 {
+		return 0, false, nil
+	}
+	return f.Digits[1], true, nil
+}
@@ -0,0 +1,105 @@
+// godoo/monetary_test.go
+package godoo
+
+import "testing"
+
+func TestRoundFloatHalfUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		rounding float64
+		digits   int
+		want     float64
+	}{
+		{"representation error rounds up", 2.675, 0.01, 2, 2.68},
+		{"half rounds up", 1.005, 0.01, 2, 1.01},
+		{"below half rounds down", 2.674, 0.01, 2, 2.67},
+		{"negative half rounds away from zero", -2.675, 0.01, 2, -2.68},
+		{"negative below half", -2.674, 0.01, 2, -2.67},
+		{"coarse rounding", 7.5, 5, 0, 10},
+		{"swiss rounding", 1.025, 0.05, 2, 1.05},
+		{"zero", 0, 0.01, 2, 0},
+		{"no rounding", 1.23456, 0, 2, 1.23456},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roundFloat(tt.value, tt.rounding, tt.digits); got != tt.want {
+				t.Errorf("roundFloat(%v, %v, %d) = %v, want %v", tt.value, tt.rounding, tt.digits, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundDecimal(t *testing.T) {
+	tests := []struct {
+		value  float64
+		digits int
+		want   float64
+	}{
+		{2.675, 2, 2.68},
+		{-0.125, 2, -0.13},
+		{1.23456, 3, 1.235},
+		{2.5, 0, 3},
+		{-2.5, 0, -3},
+	}
+	for _, tt := range tests {
+		if got := RoundDecimal(tt.value, tt.digits); got != tt.want {
+			t.Errorf("RoundDecimal(%v, %d) = %v, want %v", tt.value, tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestCurrencyRoundAndCompare(t *testing.T) {
+	usd := &Currency{Name: "USD", Rounding: 0.01, DecimalPlaces: 2}
+	tests := []struct {
+		name string
+		a, b float64
+		want int
+	}{
+		{"equal after rounding", 0.1 + 0.2, 0.3, 0},
+		{"less", 1.00, 1.01, -1},
+		{"greater", 1.02, 1.01, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := usd.Compare(tt.a, tt.b); got != tt.want {
+				t.Errorf("Compare(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+	if got := usd.Round(-10.005); got != -10.01 {
+		t.Errorf("Round(-10.005) = %v, want -10.01", got)
+	}
+	if !usd.IsZero(0.004) || usd.IsZero(0.005) {
+		t.Errorf("IsZero: 0.004 should round to zero and 0.005 should not")
+	}
+}
+
+func TestMonetaryUnmarshalOdoo(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    float64
+		wantErr bool
+	}{
+		{"float64", 12.5, 12.5, false},
+		{"int64", int64(40), 40, false},
+		{"int32", int32(7), 7, false},
+		{"int", 3, 3, false},
+		{"false", false, 0, false},
+		{"nil", nil, 0, false},
+		{"string", "12.5", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Monetary{Amount: 99}
+			err := m.UnmarshalOdoo(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalOdoo(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && m.Amount != tt.want {
+				t.Errorf("UnmarshalOdoo(%v) = %v, want %v", tt.value, m.Amount, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// godoo/null.go
+package godoo
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Odoo has no null over XML-RPC: empty char, numeric, date and relational fields are read
+// as `false`. The Null types below tell an empty field apart from a zero value; they read
+// `false` as Valid=false and write `false` when not Valid.
+
+// NullString is a char/text/selection field that may be empty.
+type NullString struct {
+	String string
+	Valid  bool // Valid is true if the field is set
+}
+
+// UnmarshalOdoo decodes a string, or `false` as an empty field.
+func (n *NullString) UnmarshalOdoo(value interface{}) error {
+	switch v := value.(type) {
+	case nil, bool:
+		*n = NullString{}
+	case string:
+		*n = NullString{String: v, Valid: true}
+	default:
+		return fmt.Errorf("%w: cannot decode %T into NullString", ErrInvalidResponse, value)
+	}
+	return nil
+}
+
+// MarshalOdoo encodes the string, or false when not Valid.
+func (n NullString) MarshalOdoo() (interface{}, error) {
+	if !n.Valid {
+		return false, nil
+	}
+	return n.String, nil
+}
+
+// MarshalJSON encodes the string, or null when not Valid.
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.String)
+}
+
+// UnmarshalJSON decodes a string, null or false.
+func (n *NullString) UnmarshalJSON(data []byte) error {
+	return unmarshalNullJSON(data, n)
+}
+
+// NullInt64 is an integer field that may be empty.
+type NullInt64 struct {
+	Int64 int64
+	Valid bool // Valid is true if the field is set
+}
+
+// UnmarshalOdoo decodes an integer, or `false` as an empty field.
+func (n *NullInt64) UnmarshalOdoo(value interface{}) error {
+	switch v := value.(type) {
+	case nil, bool:
+		*n = NullInt64{}
+		return nil
+	default:
+		i, ok := toID(v)
+		if !ok {
+			return fmt.Errorf("%w: cannot decode %T into NullInt64", ErrInvalidResponse, value)
+		}
+		*n = NullInt64{Int64: i, Valid: true}
+		return nil
+	}
+}
+
+// MarshalOdoo encodes the integer, or false when not Valid.
+func (n NullInt64) MarshalOdoo() (interface{}, error) {
+	if !n.Valid {
+		return false, nil
+	}
+	return n.Int64, nil
+}
+
+// MarshalJSON encodes the integer, or null when not Valid.
+func (n NullInt64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Int64)
+}
+
+// UnmarshalJSON decodes a number, null or false.
+func (n *NullInt64) UnmarshalJSON(data []byte) error {
+	return unmarshalNullJSON(data, n)
+}
+
+// NullFloat is a float or monetary field that may be empty.
+type NullFloat struct {
+	Float64 float64
+	Valid   bool // Valid is true if the field is set
+}
+
+// UnmarshalOdoo decodes a number, or `false` as an empty field.
+func (n *NullFloat) UnmarshalOdoo(value interface{}) error {
+	switch v := value.(type) {
+	case nil, bool:
+		*n = NullFloat{}
+	case float64:
+		*n = NullFloat{Float64: v, Valid: true}
+	default:
+		// Integers arrive as int64 (XML-RPC <i8>), int32 (<int>) or int.
+		i, ok := toID(v)
+		if !ok {
+			return fmt.Errorf("%w: cannot decode %T into NullFloat", ErrInvalidResponse, value)
+		}
+		*n = NullFloat{Float64: float64(i), Valid: true}
+	}
+	return nil
+}
+
+// MarshalOdoo encodes the number, or false when not Valid.
+func (n NullFloat) MarshalOdoo() (interface{}, error) {
+	if !n.Valid {
+		return false, nil
+	}
+	return n.Float64, nil
+}
+
+// MarshalJSON encodes the number, or null when not Valid.
+func (n NullFloat) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+// UnmarshalJSON decodes a number, null or false.
+func (n *NullFloat) UnmarshalJSON(data []byte) error {
+	return unmarshalNullJSON(data, n)
+}
+
+// NullMany2One is a many2one field that may be empty, with an explicit Valid flag like the
+// other Null types (a plain Many2One uses ID 0 for an empty field).
+type NullMany2One struct {
+	Many2One
+	Valid bool // Valid is true if the field points to a record
+}
+
+// UnmarshalOdoo decodes [id, "name"], a bare id, or `false` as an empty field.
+func (n *NullMany2One) UnmarshalOdoo(value interface{}) error {
+	var m Many2One
+	if err := m.UnmarshalOdoo(value); err != nil {
+		return err
+	}
+	*n = NullMany2One{Many2One: m, Valid: m.ID != 0}
+	return nil
+}
+
+// MarshalOdoo encodes the id, or false when not Valid.
+func (n NullMany2One) MarshalOdoo() (interface{}, error) {
+	if !n.Valid {
+		return false, nil
+	}
+	return n.ID, nil
+}
+
+// MarshalJSON encodes [id, "name"] as Odoo does, or null when not Valid.
+func (n NullMany2One) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal([]interface{}{n.ID, n.Name})
+}
+
+// UnmarshalJSON decodes [id, "name"], an id, null or false.
+func (n *NullMany2One) UnmarshalJSON(data []byte) error {
+	return unmarshalNullJSON(data, n)
+}
+
+// unmarshalNullJSON decodes JSON data generically and hands it to dst's UnmarshalOdoo.
+func unmarshalNullJSON(data []byte, dst Unmarshaler) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return dst.UnmarshalOdoo(normalizeJSON(v))
+}
+
+// GetString returns a char-like field of a raw record; ok is false when the field is
+// empty (`false`), missing or not a string.
+func GetString(record map[string]interface{}, field string) (value string, ok bool) {
+	value, ok = record[field].(string)
+	return value, ok
+}
+
+// GetInt64 returns an integer field of a raw record; ok is false when the field is empty
+// (`false`), missing or not a number.
+func GetInt64(record map[string]interface{}, field string) (int64, bool) {
+	return toID(record[field])
+}
+
+// GetFloat returns a float or monetary field of a raw record; ok is false when the field
+// is empty (`false`), missing or not a number.
+func GetFloat(record map[string]interface{}, field string) (float64, bool) {
+	if v, ok := record[field].(float64); ok {
+		return v, true
+	}
+	if i, ok := toID(record[field]); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
+
+// GetMany2One returns a many2one field of a raw record; ok is false when it is empty.
+func GetMany2One(record map[string]interface{}, field string) (Many2One, bool) {
+	var m Many2One
+	if err := m.UnmarshalOdoo(record[field]); err != nil || m.ID == 0 {
+		return Many2One{}, false
+	}
+	return m, true
+}
+
+// Normalize returns a copy of record where the `false` Odoo sends for empty non-boolean
+// fields is replaced with nil, using the field types of the schema, so that type assertions
+// such as record["email"].(string) can be checked with the comma-ok form without confusing
+// an empty field with a boolean. Boolean fields and fields unknown to the schema are kept.
+// record itself is not modified.
+func (s *Schema) Normalize(record map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(record))
+	for name, value := range record {
+		out[name] = value
+		f := s.Fields[name]
+		if f == nil || f.Type == FieldBoolean {
+			continue
+		}
+		if b, ok := value.(bool); ok && !b {
+			out[name] = nil
+		}
+	}
+	return out
+}
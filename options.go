@@ -0,0 +1,85 @@
+// godoo/options.go
+package godoo
+
+// MergeOptions combines several Options into one, from left to right:
+//   - Limit, Offset and Order of a later Options override earlier ones when set
+//     (non-zero / non-empty), so an unset field never erases a previous value.
+//   - Context and Extra are merged key by key, later keys winning.
+//
+// Nil entries are skipped. The inputs are never modified.
+func MergeOptions(options ...*Options) *Options {
+	merged := &Options{}
+	for _, o := range options {
+		if o == nil {
+			continue
+		}
+		if o.Limit > 0 {
+			merged.Limit = o.Limit
+		}
+		if o.Offset > 0 {
+			merged.Offset = o.Offset
+		}
+		if o.Order != "" {
+			merged.Order = o.Order
+		}
+		for k, v := range o.Context {
+			if merged.Context == nil {
+				merged.Context = OdooContext{}
+			}
+			merged.Context[k] = v
+		}
+		for k, v := range o.Extra {
+			if merged.Extra == nil {
+				merged.Extra = map[string]interface{}{}
+			}
+			merged.Extra[k] = v
+		}
+	}
+	return merged
+}
+
+// Limit returns Options restricting the number of records returned.
+func Limit(n int) *Options {
+	return &Options{Limit: n}
+}
+
+// Offset returns Options skipping the first n records.
+func Offset(n int) *Options {
+	return &Options{Offset: n}
+}
+
+// Order returns Options sorting the records, e.g. Order("name asc, id desc").
+func Order(order string) *Options {
+	return &Options{Order: order}
+}
+
+// ContextValue returns Options setting a single key of the Odoo context.
+func ContextValue(key string, value interface{}) *Options {
+	return &Options{Context: OdooContext{key: value}}
+}
+
+// Lang returns Options setting the `lang` of the Odoo context, e.g. Lang("es_VE").
+func Lang(code string) *Options {
+	return ContextValue("lang", code)
+}
+
+// Timezone returns Options setting the `tz` of the Odoo context, e.g. Timezone("America/Caracas").
+func Timezone(tz string) *Options {
+	return ContextValue("tz", tz)
+}
+
+// ActiveTest returns Options setting `active_test`; ActiveTest(false) includes archived records.
+func ActiveTest(enabled bool) *Options {
+	return ContextValue("active_test", enabled)
+}
+
+// Company returns Options setting `allowed_company_ids` in the Odoo context. The first id
+// is the active company; the rest are additionally allowed, e.g. Company(3) or Company(3, 1).
+func Company(ids ...int64) *Options {
+	return ContextValue("allowed_company_ids", append([]int64(nil), ids...))
+}
+
+// Kwarg returns Options adding an arbitrary keyword argument to the call (Options.Extra).
+func Kwarg(key string, value interface{}) *Options {
+	return &Options{Extra: map[string]interface{}{key: value}}
+}
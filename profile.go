@@ -0,0 +1,300 @@
+// godoo/profile.go
+package godoo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrProfileNotFound indica que el perfil solicitado no existe en el archivo de configuración.
+var ErrProfileNotFound = errors.New("godoo: profile not found")
+
+// defaultProfileName is used when neither the caller nor GODOO_PROFILE names a profile.
+const defaultProfileName = "default"
+
+// NewFromProfile creates an OdooClient from a named profile of the godoo config file, so
+// tools and services share one connection definition. An empty name selects the profile in
+// GODOO_PROFILE, or "default". The file is the one in GODOO_CONFIG or, failing that, the
+// first that exists among <user config dir>/godoo/config.{ini,yaml,yml} and ~/.godoorc.
+//
+// INI (odoorc-style) files use one section per profile:
+//
+//	[staging]
+//	url = https://staging.example.com
+//	db = staging
+//	user = admin
+//	api_key_file = ~/.secrets/odoo-staging
+//	lang = es_VE
+//	tz = America/Caracas
+//	company = 3
+//	context.active_test = false
+//
+// YAML files use a top-level `profiles` mapping with the same keys; `context` may be a
+// nested or flow mapping, e.g. `context: {allowed_company_ids: [1, 3]}`. Context values
+// written as flow lists are sent as lists. Profile keys accept the same parameters as NewFromDSN, plus password_env /
+// api_key_env (read the secret from another variable), lang, tz, company/allowed_company_ids,
+// active_test and context.<key>, which populate the client's default OdooContext.
+func NewFromProfile(name string, opts ...Option) (*OdooClient, error) {
+	path, err := findProfileFile()
+	if err != nil {
+		return nil, err
+	}
+	return NewFromProfileFile(path, name, opts...)
+}
+
+// NewFromProfileFile is like NewFromProfile but reads the given config file.
+func NewFromProfileFile(path, name string, opts ...Option) (*OdooClient, error) {
+	if name == "" {
+		name = os.Getenv("GODOO_PROFILE")
+	}
+	if name == "" {
+		name = defaultProfileName
+	}
+
+	profiles, err := loadProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("%w: %q in %s (available: %s)", ErrProfileNotFound, name, path, strings.Join(names, ", "))
+	}
+
+	source := "profile " + name
+	cfg := &clientConfig{}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := values[k]
+		if strings.HasSuffix(k, "_file") {
+			value = expandHome(value)
+		}
+		if err := cfg.set(source, k, k, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.validate(source, map[string]string{"url": "url", "db": "db", "user": "user", "password": "password"}); err != nil {
+		return nil, err
+	}
+	return cfg.newClient(opts)
+}
+
+// findProfileFile locates the godoo config file.
+func findProfileFile() (string, error) {
+	if path := os.Getenv("GODOO_CONFIG"); path != "" {
+		return expandHome(path), nil
+	}
+
+	var candidates []string
+	if dir, err := os.UserConfigDir(); err == nil {
+		for _, name := range []string{"config.ini", "config.yaml", "config.yml"} {
+			candidates = append(candidates, filepath.Join(dir, "godoo", name))
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".godoorc"))
+	}
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no config file found (set GODOO_CONFIG or create one of %s)", ErrProfileNotFound, strings.Join(candidates, ", "))
+}
+
+// expandHome replaces a leading "~/" with the user's home directory.
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// loadProfiles reads every profile of a config file as flat key/value maps.
+func loadProfiles(path string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("godoo: failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseYAMLProfiles(f, path)
+	default:
+		return parseINIProfiles(f, path)
+	}
+}
+
+// parseINIProfiles parses an odoorc-style INI file: `[name]` sections of `key = value`
+// lines, with `;` or `#` comments. Keys outside any section are ignored.
+func parseINIProfiles(r io.Reader, path string) (map[string]map[string]string, error) {
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("godoo: %s:%d: malformed section header", path, lineNo)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			name = strings.TrimSpace(strings.TrimPrefix(name, "profile "))
+			current = map[string]string{}
+			profiles[name] = current
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, ok = strings.Cut(line, ":")
+		}
+		if !ok {
+			return nil, fmt.Errorf("godoo: %s:%d: expected key = value", path, lineNo)
+		}
+		if current == nil {
+			continue
+		}
+		current[strings.ToLower(strings.TrimSpace(key))] = unquote(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("godoo: failed to read config file: %w", err)
+	}
+	return profiles, nil
+}
+
+// parseYAMLProfiles parses the subset of YAML used by godoo config files: nested block
+// mappings of scalars, `#` comments and flow lists such as `[1, 3]`. Profiles live under a
+// top-level `profiles` key; nested mappings are flattened into dotted keys (context.lang).
+func parseYAMLProfiles(r io.Reader, path string) (map[string]map[string]string, error) {
+	type frame struct {
+		indent int
+		prefix string
+	}
+	flat := map[string]string{}
+	var stack []frame
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := scanner.Text()
+		trimmed := strings.TrimSpace(stripYAMLComment(raw))
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if strings.Contains(raw[:len(raw)-len(strings.TrimLeft(raw, " \t"))], "\t") {
+			return nil, fmt.Errorf("godoo: %s:%d: tabs are not allowed for indentation", path, lineNo)
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.HasPrefix(trimmed, "- ") {
+			return nil, fmt.Errorf("godoo: %s:%d: expected `key: value`", path, lineNo)
+		}
+		key = unquote(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		full := key
+		if len(stack) > 0 {
+			full = stack[len(stack)-1].prefix + "." + key
+		}
+
+		if value == "" {
+			stack = append(stack, frame{indent: indent, prefix: full})
+			continue
+		}
+		if err := flattenYAMLValue(flat, full, value); err != nil {
+			return nil, fmt.Errorf("godoo: %s:%d: %w", path, lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("godoo: failed to read config file: %w", err)
+	}
+
+	profiles := map[string]map[string]string{}
+	for full, value := range flat {
+		rest, ok := strings.CutPrefix(full, "profiles.")
+		if !ok {
+			continue
+		}
+		name, key, ok := strings.Cut(rest, ".")
+		if !ok {
+			return nil, fmt.Errorf("godoo: %s: profile %q must be a mapping", path, rest)
+		}
+		if profiles[name] == nil {
+			profiles[name] = map[string]string{}
+		}
+		profiles[name][strings.ToLower(key)] = value
+	}
+	return profiles, nil
+}
+
+// flattenYAMLValue stores value under key, spreading a flow mapping such as
+// `{lang: es_VE, allowed_company_ids: [1, 3]}` into dotted keys (key.lang, ...).
+// Flow lists are kept as text and parsed where they are used.
+func flattenYAMLValue(flat map[string]string, key, value string) error {
+	if len(value) < 2 || value[0] != '{' || value[len(value)-1] != '}' {
+		flat[key] = unquote(value)
+		return nil
+	}
+	for _, item := range splitFlow(value[1 : len(value)-1]) {
+		k, v, ok := strings.Cut(item, ":")
+		if !ok {
+			return fmt.Errorf("expected `key: value` in %s", value)
+		}
+		if err := flattenYAMLValue(flat, key+"."+unquote(strings.TrimSpace(k)), strings.TrimSpace(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stripYAMLComment removes a trailing `# comment` that is not inside quotes.
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquote strips matching single or double quotes around a scalar.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,265 @@
+// godoo/record.go
+package godoo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Unmarshaler is implemented by types that decode themselves from a raw Odoo field value
+// (as read over RPC: `false` for empty fields, [id, "name"] for many2one, ...).
+type Unmarshaler interface {
+	UnmarshalOdoo(value interface{}) error
+}
+
+// Marshaler is implemented by types that encode themselves into the value Odoo expects
+// when writing the field.
+type Marshaler interface {
+	MarshalOdoo() (interface{}, error)
+}
+
+var (
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+)
+
+// UnmarshalOdoo decodes [id, "name"], a bare id or `false`.
+func (m *Many2One) UnmarshalOdoo(value interface{}) error {
+	switch value.(type) {
+	case nil, bool:
+		*m = Many2One{}
+		return nil
+	case []interface{}, int64, int, int32, float64:
+		m.ID, m.Name = many2oneID(value)
+		return nil
+	}
+	return fmt.Errorf("%w: cannot decode %T into Many2One", ErrInvalidResponse, value)
+}
+
+// MarshalOdoo encodes the id, or false when empty.
+func (m Many2One) MarshalOdoo() (interface{}, error) {
+	return m.ToRPC(), nil
+}
+
+// structField describes a struct field mapped to an Odoo field with an `odoo:"name"` tag.
+// Tag options: `readonly` excludes the field from create and write.
+type structField struct {
+	name     string
+	index    []int
+	readonly bool
+	currency string // currency field of a Monetary field
+}
+
+// structInfo is the Odoo mapping of a struct type.
+type structInfo struct {
+	fields  []structField
+	byName  map[string]*structField
+	idIndex []int // index of the `odoo:"id"` field, nil if absent
+}
+
+var structInfoCache sync.Map // reflect.Type -> *structInfo
+
+// getStructInfo returns the (cached) Odoo mapping of struct type t. Only fields tagged
+// `odoo:"..."` are mapped; untagged embedded structs are flattened.
+func getStructInfo(t reflect.Type) *structInfo {
+	if cached, ok := structInfoCache.Load(t); ok {
+		return cached.(*structInfo)
+	}
+	info := &structInfo{byName: map[string]*structField{}}
+	collectStructFields(t, nil, info)
+	for i := range info.fields {
+		f := &info.fields[i]
+		info.byName[f.name] = f
+		if f.name == "id" {
+			info.idIndex = f.index
+		}
+	}
+	cached, _ := structInfoCache.LoadOrStore(t, info)
+	return cached.(*structInfo)
+}
+
+func collectStructFields(t reflect.Type, parent []int, info *structInfo) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		index := append(parent[:len(parent):len(parent)], i)
+		tag, tagged := sf.Tag.Lookup("odoo")
+		if !tagged {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				collectStructFields(sf.Type, index, info)
+			}
+			continue
+		}
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		f := structField{name: parts[0], index: index}
+		for _, opt := range parts[1:] {
+			switch {
+			case opt == "readonly":
+				f.readonly = true
+			case strings.HasPrefix(opt, "currency="):
+				f.currency = strings.TrimPrefix(opt, "currency=")
+			}
+		}
+		if f.currency == "" && sf.Type == reflect.TypeOf(Monetary{}) {
+			f.currency = "currency_id"
+		}
+		if f.name != "" {
+			info.fields = append(info.fields, f)
+		}
+	}
+}
+
+// names returns the Odoo field names to read for the mapping: the tagged fields and the
+// currency fields of Monetary fields.
+func (s *structInfo) names() Fields {
+	names := make(Fields, 0, len(s.fields))
+	seen := map[string]bool{}
+	for _, f := range s.fields {
+		names = append(names, f.name)
+		seen[f.name] = true
+	}
+	for _, f := range s.fields {
+		if f.currency != "" && !seen[f.currency] {
+			names = append(names, f.currency)
+			seen[f.currency] = true
+		}
+	}
+	return names
+}
+
+// decodeStruct stores a record dictionary into the tagged fields of dst. Keys without a
+// matching field are ignored.
+func decodeStruct(record map[string]interface{}, dst reflect.Value) error {
+	info := getStructInfo(dst.Type())
+	for _, f := range info.fields {
+		value, ok := record[f.name]
+		if !ok {
+			continue
+		}
+		field := dst.FieldByIndex(f.index)
+		if err := assignValue(value, field); err != nil {
+			return fmt.Errorf("field '%s': %w", f.name, err)
+		}
+		if m, ok := field.Addr().Interface().(*Monetary); ok && f.currency != "" {
+			if err := m.Currency.UnmarshalOdoo(record[f.currency]); err != nil {
+				return fmt.Errorf("field '%s': %w", f.currency, err)
+			}
+		}
+	}
+	return nil
+}
+
+// encodeStruct converts the writable tagged fields of src into Data. With skipZero, zero
+// values are left out so that Odoo applies the field defaults (use a pointer field to
+// send an explicit zero on create). Only the named fields are encoded when only is non-nil.
+func encodeStruct(src reflect.Value, skipZero bool, only map[string]bool) (Data, error) {
+	info := getStructInfo(src.Type())
+	data := Data{}
+	for _, f := range info.fields {
+		if f.readonly || f.name == "id" || (only != nil && !only[f.name]) {
+			continue
+		}
+		v := src.FieldByIndex(f.index)
+		if skipZero && v.IsZero() {
+			continue
+		}
+		value, err := encodeValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", f.name, err)
+		}
+		data[f.name] = value
+	}
+	return data, nil
+}
+
+// encodeValue converts a Go value to its Odoo write representation: Marshaler types encode
+// themselves, nil pointers and empty strings become false, and integer slices become an
+// x2many "replace" command [(6, 0, ids)].
+func encodeValue(v reflect.Value) (interface{}, error) {
+	if v.Type().Implements(marshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return false, nil
+		}
+		return v.Interface().(Marshaler).MarshalOdoo()
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(marshalerType) {
+		return v.Addr().Interface().(Marshaler).MarshalOdoo()
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		return DateTimeOf(t).MarshalOdoo()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return false, nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.String:
+		if v.Len() == 0 {
+			return false, nil
+		}
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Slice:
+		switch v.Type().Elem().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			ids := make([]int64, v.Len())
+			for i := range ids {
+				ids[i] = v.Index(i).Int()
+			}
+			return []interface{}{X2ManySet(ids)}, nil
+		}
+	}
+	return v.Interface(), nil
+}
+
+// fieldsEqual compares two values of the same struct field as Odoo would see them: Marshaler
+// values (Many2One, Date...) by their encoded form and integer slices (x2many ids) as sets.
+func fieldsEqual(a, b reflect.Value) bool {
+	if a.Type().Implements(marshalerType) {
+		x, errA := a.Interface().(Marshaler).MarshalOdoo()
+		y, errB := b.Interface().(Marshaler).MarshalOdoo()
+		if errA == nil && errB == nil {
+			return reflect.DeepEqual(x, y)
+		}
+	}
+	if a.Kind() == reflect.Slice {
+		switch a.Type().Elem().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return sameIDs(a, b)
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func sameIDs(a, b reflect.Value) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	x := make([]int64, a.Len())
+	y := make([]int64, b.Len())
+	for i := range x {
+		x[i], y[i] = a.Index(i).Int(), b.Index(i).Int()
+	}
+	sort.Slice(x, func(i, j int) bool { return x[i] < x[j] })
+	sort.Slice(y, func(i, j int) bool { return y[i] < y[j] })
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
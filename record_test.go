@@ -0,0 +1,54 @@
+// godoo/record_test.go
+package godoo
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Relation types as generated by godoo-gen.
+type testPartnerRef struct {
+	Many2One
+}
+
+type testTagIDs []int64
+
+type testOrder struct {
+	ID        int64          `odoo:"id"`
+	PartnerID testPartnerRef `odoo:"partner_id"`
+	TagIDs    testTagIDs     `odoo:"tag_ids"`
+}
+
+func TestStructsWithTypedRelations(t *testing.T) {
+	var order testOrder
+	record := map[string]interface{}{
+		"id":         int64(7),
+		"partner_id": []interface{}{int64(3), "Azure Interior"},
+		"tag_ids":    []interface{}{int64(1), int64(2)},
+	}
+	if err := decodeStruct(record, reflect.ValueOf(&order).Elem()); err != nil {
+		t.Fatalf("decodeStruct: %v", err)
+	}
+	if order.PartnerID.ID != 3 || order.PartnerID.Name != "Azure Interior" || !reflect.DeepEqual(order.TagIDs, testTagIDs{1, 2}) {
+		t.Errorf("decoded %+v", order)
+	}
+
+	data, err := encodeStruct(reflect.ValueOf(order), false, nil)
+	if err != nil {
+		t.Fatalf("encodeStruct: %v", err)
+	}
+	want := Data{"partner_id": int64(3), "tag_ids": []interface{}{X2ManySet([]int64{1, 2})}}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("encoded %v, want %v", data, want)
+	}
+
+	changed := order
+	changed.TagIDs = testTagIDs{2, 1}
+	if !fieldsEqual(reflect.ValueOf(order.TagIDs), reflect.ValueOf(changed.TagIDs)) {
+		t.Error("the same ids in another order compare as changed")
+	}
+	changed.PartnerID = testPartnerRef{Many2One{ID: 4}}
+	if fieldsEqual(reflect.ValueOf(order.PartnerID), reflect.ValueOf(changed.PartnerID)) {
+		t.Error("a different partner compares as unchanged")
+	}
+}
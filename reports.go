@@ -0,0 +1,219 @@
+// godoo/reports.go
+package godoo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ReportType is the output type of an ir.actions.report.
+type ReportType string
+
+const (
+	ReportPDF  ReportType = "qweb-pdf"  // PDF rendered by wkhtmltopdf
+	ReportHTML ReportType = "qweb-html" // HTML page
+	ReportText ReportType = "qweb-text" // Plain text, e.g. ZPL labels
+)
+
+// contentType returns the media type the /report/<format>/ route answers with.
+func (t ReportType) contentType() string {
+	switch t {
+	case ReportPDF:
+		return "application/pdf"
+	case ReportHTML:
+		return "text/html"
+	}
+	return "text/plain"
+}
+
+// routeFormat returns the /report/<format>/ route segment of the type.
+func (t ReportType) routeFormat() (string, bool) {
+	switch t {
+	case ReportPDF:
+		return "pdf", true
+	case ReportHTML:
+		return "html", true
+	case ReportText:
+		return "text", true
+	}
+	return "", false
+}
+
+// Report describes an ir.actions.report.
+type Report struct {
+	ID         int64
+	Name       string     // Printed name, e.g. "Invoices"
+	ReportName string     // QWeb template, e.g. "account.report_invoice"
+	Type       ReportType // Output type
+	Model      Model      // Model of the records the report prints
+}
+
+// Report resolves an ir.actions.report from its XML ID (e.g. "account.account_invoices")
+// or its report_name (e.g. "account.report_invoice"). A missing report yields a
+// *ReportError matching ErrReportNotFound.
+func (c *OdooClient) Report(ctx context.Context, ref string, options ...*Options) (*Report, error) {
+	var id int64
+	if _, _, err := splitXMLID(ref); err == nil {
+		refs, err := c.Refs(ctx, []string{ref})
+		if err != nil {
+			return nil, &ReportError{Report: ref, Err: err}
+		}
+		if r, ok := refs[ref]; ok && r.Model == ModelIrActionsReport {
+			id = r.ID
+		}
+	}
+	if id == 0 {
+		found, err := c.Search(ctx, ModelIrActionsReport, Domain{{"report_name", "=", ref}}, Limit(1))
+		if err != nil {
+			return nil, &ReportError{Report: ref, Err: err}
+		}
+		if len(found) == 0 {
+			return nil, &ReportError{Report: ref, Err: ErrReportNotFound}
+		}
+		id = found[0]
+	}
+
+	record, err := c.ReadOne(ctx, ModelIrActionsReport, id, Fields{"name", "report_name", "report_type", "model"}, options...)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			err = ErrReportNotFound
+		}
+		return nil, &ReportError{Report: ref, Err: err}
+	}
+	report := &Report{ID: id}
+	report.Name, _ = GetString(record, "name")
+	report.ReportName, _ = GetString(record, "report_name")
+	kind, _ := GetString(record, "report_type")
+	report.Type = ReportType(kind)
+	model, _ := GetString(record, "model")
+	report.Model = Model(model)
+	return report, nil
+}
+
+// RenderReport renders the report ref (see Report) for the records ids and streams the
+// output (PDF, HTML or text, depending on the report type) to w.
+//
+// With TransportWebSession the report is fetched from the /report/<format>/ route and
+// copied to w as it arrives. Other transports render through ir.actions.report's
+// render_qweb_* methods, which Odoo only exposes over RPC before version 14; on newer
+// servers they open a web session with the client's password to use the route (API keys
+// and bearer tokens cannot, and get an error). The context of options (e.g. Lang) is
+// passed on to the rendering.
+func (c *OdooClient) RenderReport(ctx context.Context, ref string, ids []int64, w io.Writer, options ...*Options) (*Report, error) {
+	c.logger.Debug("Rendering Odoo report",
+		zap.String("report", ref),
+		zap.Any("ids", ids),
+		zap.String("op", "RenderReport"),
+	)
+
+	report, err := c.Report(ctx, ref, options...)
+	if err != nil {
+		return nil, err
+	}
+	format, ok := report.Type.routeFormat()
+	if !ok {
+		return report, &ReportError{Report: ref, Err: fmt.Errorf("%w: report type %q", ErrReportUnsupported, report.Type)}
+	}
+	if len(ids) == 0 {
+		return report, &ReportError{Report: ref, Err: errors.New("no record ids to render")}
+	}
+
+	var written int64
+	if c.transport == TransportWebSession {
+		written, err = c.renderReportRoute(ctx, report, format, ids, w, options...)
+	} else {
+		written, err = c.renderReportRPC(ctx, report, format, ids, w, options...)
+	}
+	if err != nil {
+		err = &ReportError{Report: ref, Err: err}
+		c.logger.Error("Odoo report rendering failed",
+			zap.Error(err),
+			zap.String("report", report.ReportName),
+			zap.String("op", "RenderReport"),
+		)
+		return report, err
+	}
+
+	c.logger.Info("Odoo report rendered",
+		zap.String("report", report.ReportName),
+		zap.String("type", string(report.Type)),
+		zap.Int("records_count", len(ids)),
+		zap.Int64("size", written),
+		zap.String("op", "RenderReport"),
+	)
+	return report, nil
+}
+
+// renderReportRoute streams the report from /report/<format>/<report_name>/<ids>.
+func (c *OdooClient) renderReportRoute(ctx context.Context, report *Report, format string, ids []int64, w io.Writer, options ...*Options) (int64, error) {
+	docIDs := make([]string, len(ids))
+	for i, id := range ids {
+		docIDs[i] = strconv.FormatInt(id, 10)
+	}
+	path := fmt.Sprintf("/report/%s/%s/%s", format, url.PathEscape(report.ReportName), strings.Join(docIDs, ","))
+	if opts := MergeOptions(options...); len(opts.Context) > 0 {
+		raw, err := json.Marshal(opts.Context)
+		if err != nil {
+			return 0, fmt.Errorf("godoo: failed to encode report context: %w", err)
+		}
+		path += "?context=" + url.QueryEscape(string(raw))
+	}
+
+	resp, err := c.WebRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Guard against streaming out an HTML error or login page served with a 200 status.
+	want := report.Type.contentType()
+	if got, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); got != want {
+		return 0, fmt.Errorf("%w: report route returned Content-Type %q, expected %s", ErrInvalidResponse, resp.Header.Get("Content-Type"), want)
+	}
+	return io.Copy(w, resp.Body)
+}
+
+// renderReportRPC calls render_qweb_<format> on the report, available over RPC up to Odoo 13.
+// Newer servers render through the route of a web session opened with the same credentials.
+func (c *OdooClient) renderReportRPC(ctx context.Context, report *Report, format string, ids []int64, w io.Writer, options ...*Options) (int64, error) {
+	version, err := c.ServerVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if version.AtLeast(14, 0) {
+		web, err := c.webSession()
+		if err != nil {
+			return 0, fmt.Errorf("%w: Odoo %s only renders reports through a web session: %v", ErrReportUnsupported, version.Series, err)
+		}
+		return web.renderReportRoute(ctx, report, format, ids, w, options...)
+	}
+
+	// The method returns a (content, format) pair.
+	var result []interface{}
+	err = c.executeRPC(ctx, string(ModelIrActionsReport), "render_qweb_"+format, []interface{}{[]int64{report.ID}, ids}, c.parseOptions(options...), &result)
+	if err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, fmt.Errorf("%w: empty render_qweb_%s result", ErrInvalidResponse, format)
+	}
+	// XML-RPC delivers the content as <base64> (decoded to []byte); JSON transports as text.
+	switch content := result[0].(type) {
+	case []byte:
+		n, err := w.Write(content)
+		return int64(n), err
+	case string:
+		n, err := io.WriteString(w, content)
+		return int64(n), err
+	}
+	return 0, fmt.Errorf("%w: render_qweb_%s returned %T", ErrInvalidResponse, format, result[0])
+}
@@ -0,0 +1,344 @@
+// godoo/repository.go
+package godoo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// Repository provides typed CRUD access to one Odoo model. T is a struct whose fields are
+// mapped with `odoo:"field_name"` tags (as generated by godoo-gen); it must map the `id`
+// field to an integer. The fields read are exactly the tagged ones, and fields tagged
+// `odoo:"name,readonly"` are never written.
+//
+// Field values follow Odoo's conventions: `false` reads as the zero value, empty strings and
+// nil pointers are written as `false`, many2one fields use Many2One and x2many fields
+// []int64 (written as a replace command). Types implementing Unmarshaler and Marshaler
+// control their own conversion.
+//
+// Records are returned as Tracked values, which keep the values they were read with so that
+// Save writes only the fields changed since. The repository itself holds no per-record
+// state and is safe for concurrent use.
+type Repository[T any] struct {
+	client *OdooClient
+	model  Model
+	info   *structInfo
+	fields Fields
+}
+
+// Tracked is a record of a Repository together with its values as last read, created or
+// saved, against which Save compares Value to write only what changed. Edit Value in place.
+// A Tracked built by hand (e.g. &Tracked[Partner]{Value: p}) has no snapshot: saving it
+// writes every writable field, or creates the record when it has no id.
+type Tracked[T any] struct {
+	Value T
+
+	original *T // values as last read or saved, nil when unknown
+}
+
+// DirtyFields returns the writable fields whose value differs from the snapshot, in struct
+// order; all of them when there is no snapshot.
+func (t *Tracked[T]) DirtyFields() []string {
+	info := getStructInfo(reflect.TypeOf(t.Value))
+	changed := changedFields(info, t.original, &t.Value)
+	var names []string
+	for _, f := range info.fields {
+		if changed[f.name] {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// IsDirty reports whether Save would write anything.
+func (t *Tracked[T]) IsDirty() bool {
+	return len(t.DirtyFields()) > 0
+}
+
+// Reset discards the changes made to Value since the snapshot. Without snapshot it does
+// nothing.
+func (t *Tracked[T]) Reset() {
+	if t.original != nil {
+		t.Value = *snapshotOf(getStructInfo(reflect.TypeOf(t.Value)), t.original)
+	}
+}
+
+// NewRepository creates a Repository of model for records of type T. It panics if T is not
+// a struct with an `odoo:"id"` integer field, which is a programming error.
+func NewRepository[T any](client *OdooClient, model Model) *Repository[T] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("godoo: NewRepository: %s is not a struct", t))
+	}
+	info := getStructInfo(t)
+	if info.idIndex == nil {
+		panic(fmt.Sprintf("godoo: NewRepository: %s has no field tagged `odoo:\"id\"`", t))
+	}
+	switch t.FieldByIndex(info.idIndex).Type.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+	default:
+		panic(fmt.Sprintf("godoo: NewRepository: the id field of %s must be an integer", t))
+	}
+	return &Repository[T]{client: client, model: model, info: info, fields: info.names()}
+}
+
+// Model returns the Odoo model of the repository.
+func (r *Repository[T]) Model() Model {
+	return r.model
+}
+
+// Fields returns the Odoo fields read by the repository (the tagged fields of T).
+func (r *Repository[T]) Fields() Fields {
+	return append(Fields(nil), r.fields...)
+}
+
+// idOf returns the id stored in rec.
+func (r *Repository[T]) idOf(rec *T) int64 {
+	return reflect.ValueOf(rec).Elem().FieldByIndex(r.info.idIndex).Int()
+}
+
+// decode converts raw records into tracked T values.
+func (r *Repository[T]) decode(records []map[string]interface{}) ([]Tracked[T], error) {
+	out := make([]Tracked[T], len(records))
+	for i, record := range records {
+		if err := decodeStruct(record, reflect.ValueOf(&out[i].Value).Elem()); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidResponse, r.model, err)
+		}
+		r.remember(&out[i])
+	}
+	return out, nil
+}
+
+// remember makes the current Value of rec its snapshot.
+func (r *Repository[T]) remember(rec *Tracked[T]) {
+	rec.original = snapshotOf(r.info, &rec.Value)
+}
+
+// snapshotOf returns a copy of the mapped fields of rec, so later changes to its slices or
+// pointers do not alter the copy.
+func snapshotOf[T any](info *structInfo, rec *T) *T {
+	snap := new(T)
+	src := reflect.ValueOf(rec).Elem()
+	dst := reflect.ValueOf(snap).Elem()
+	for _, f := range info.fields {
+		dst.FieldByIndex(f.index).Set(copyValue(src.FieldByIndex(f.index)))
+	}
+	return snap
+}
+
+// copyValue returns a copy of v that shares no slice, map or pointer with it.
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return out
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(copyValue(v.Elem()))
+		return out
+	}
+	return v
+}
+
+// Find returns the records matching domain, using search_read. Limit, offset and order are
+// taken from the options.
+func (r *Repository[T]) Find(ctx context.Context, domain Domain, options ...*Options) ([]Tracked[T], error) {
+	r.client.logger.Debug("Performing Odoo repository find",
+		zap.String("model", string(r.model)),
+		zap.Any("domain", domain),
+		zap.String("op", "Repository.Find"),
+	)
+
+	var records []map[string]interface{}
+	err := r.client.executeRPC(ctx, string(r.model), "search_read", []interface{}{domain.ToRPC(), r.fields.ToRPC()}, r.client.parseOptions(options...), &records)
+	if err != nil {
+		return nil, err
+	}
+	return r.decode(records)
+}
+
+// FindOne returns the first record matching domain, or ErrRecordNotFound.
+func (r *Repository[T]) FindOne(ctx context.Context, domain Domain, options ...*Options) (*Tracked[T], error) {
+	records, err := r.Find(ctx, domain, MergeOptions(append(options[:len(options):len(options)], Limit(1))...))
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: for model '%s' with domain %v", ErrRecordNotFound, string(r.model), domain.ToRPC())
+	}
+	return &records[0], nil
+}
+
+// Get reads the record with the given id, or returns ErrRecordNotFound.
+func (r *Repository[T]) Get(ctx context.Context, id int64, options ...*Options) (*Tracked[T], error) {
+	out, err := r.get(ctx, id, options...)
+	if err != nil {
+		return nil, err
+	}
+	rec := &Tracked[T]{Value: *out}
+	r.remember(rec)
+	return rec, nil
+}
+
+// get reads the record with the given id without tracking it.
+func (r *Repository[T]) get(ctx context.Context, id int64, options ...*Options) (*T, error) {
+	record, err := r.client.ReadOne(ctx, r.model, id, r.fields, options...)
+	if err != nil {
+		return nil, err
+	}
+	out := new(T)
+	if err := decodeStruct(record, reflect.ValueOf(out).Elem()); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidResponse, r.model, err)
+	}
+	return out, nil
+}
+
+// GetMany reads the records with the given ids. Ids that do not exist are left out.
+func (r *Repository[T]) GetMany(ctx context.Context, ids []int64, options ...*Options) ([]Tracked[T], error) {
+	records, err := r.client.Read(ctx, r.model, ids, r.fields, options...)
+	if err != nil {
+		return nil, err
+	}
+	return r.decode(records)
+}
+
+// Count returns the number of records matching domain.
+func (r *Repository[T]) Count(ctx context.Context, domain Domain, options ...*Options) (int64, error) {
+	var count int64
+	err := r.client.executeRPC(ctx, string(r.model), "search_count", []interface{}{domain.ToRPC()}, r.client.parseOptions(options...), &count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Create creates rec.Value, stores the new id in it and makes it the snapshot. Zero-valued
+// fields are not sent, so the model's defaults apply to them; they stay zero in the snapshot,
+// so a later Save writes them only once they are set.
+func (r *Repository[T]) Create(ctx context.Context, rec *Tracked[T], options ...*Options) (int64, error) {
+	data, err := encodeStruct(reflect.ValueOf(&rec.Value).Elem(), true, nil)
+	if err != nil {
+		return 0, fmt.Errorf("godoo: %s: %w", r.model, err)
+	}
+	id, err := r.client.CreateOne(ctx, r.model, data, options...)
+	if err != nil {
+		return 0, err
+	}
+	reflect.ValueOf(&rec.Value).Elem().FieldByIndex(r.info.idIndex).SetInt(id)
+	r.remember(rec)
+	return id, nil
+}
+
+// Save writes the fields of rec.Value that differ from its snapshot, i.e. the values it was
+// read, created or last saved with, and does nothing when none changed. Odoo is not read
+// again, so fields changed meanwhile by other users are left alone. Without snapshot every
+// writable field is written. A record without id is created instead.
+func (r *Repository[T]) Save(ctx context.Context, rec *Tracked[T], options ...*Options) error {
+	id := r.idOf(&rec.Value)
+	if id == 0 {
+		_, err := r.Create(ctx, rec, options...)
+		return err
+	}
+
+	_, err := r.saveChanges(ctx, id, rec.original, rec, options...)
+	return err
+}
+
+// saveChanges writes the fields of rec.Value that differ from base (all writable fields when
+// base is nil), reporting whether anything was written. rec.Value becomes the snapshot.
+func (r *Repository[T]) saveChanges(ctx context.Context, id int64, base *T, rec *Tracked[T], options ...*Options) (bool, error) {
+	changed := changedFields(r.info, base, &rec.Value)
+	if len(changed) == 0 {
+		r.client.logger.Debug("No changes to save",
+			zap.String("model", string(r.model)),
+			zap.Int64("id", id),
+			zap.String("op", "Repository.Save"),
+		)
+		return false, nil
+	}
+
+	data, err := encodeStruct(reflect.ValueOf(&rec.Value).Elem(), false, changed)
+	if err != nil {
+		return false, fmt.Errorf("godoo: %s: %w", r.model, err)
+	}
+	if _, err := r.client.Update(ctx, r.model, []int64{id}, data, options...); err != nil {
+		return false, err
+	}
+	r.remember(rec)
+	return true, nil
+}
+
+// changedFields returns the writable fields whose values differ between old and rec, or
+// all of them when old is nil.
+func changedFields[T any](info *structInfo, old, rec *T) map[string]bool {
+	nv := reflect.ValueOf(rec).Elem()
+	changed := map[string]bool{}
+	for _, f := range info.fields {
+		if f.readonly || f.name == "id" {
+			continue
+		}
+		if old == nil || !fieldsEqual(reflect.ValueOf(old).Elem().FieldByIndex(f.index), nv.FieldByIndex(f.index)) {
+			changed[f.name] = true
+		}
+	}
+	return changed
+}
+
+// Delete deletes the records with the given ids.
+func (r *Repository[T]) Delete(ctx context.Context, ids ...int64) error {
+	_, err := r.client.Delete(ctx, r.model, ids)
+	return err
+}
+
+// Each calls fn for every record matching domain, reading batchSize records at a time
+// (default 100) in id order, so records created or deleted meanwhile do not shift pages.
+// fn may Save the record it is given. Iteration stops at the first error returned by fn,
+// or when ctx is done.
+func (r *Repository[T]) Each(ctx context.Context, domain Domain, batchSize int, fn func(rec *Tracked[T]) error, options ...*Options) error {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	opts := MergeOptions(append(options[:len(options):len(options)], Order("id"), Limit(batchSize))...)
+	var lastID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page := append(domain[:len(domain):len(domain)], DomainCondition{"id", ">", lastID})
+		records, err := r.Find(ctx, page, opts)
+		if err != nil {
+			return err
+		}
+		for i := range records {
+			if err := fn(&records[i]); err != nil {
+				return err
+			}
+		}
+		if len(records) < batchSize {
+			return nil
+		}
+		lastID = r.idOf(&records[len(records)-1].Value)
+	}
+}
@@ -0,0 +1,187 @@
+// godoo/repository_test.go
+package godoo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type testPartner struct {
+	ID         int64   `odoo:"id"`
+	Name       string  `odoo:"name"`
+	Email      string  `odoo:"email"`
+	CategoryID []int64 `odoo:"category_id"`
+	WriteDate  string  `odoo:"write_date,readonly"`
+}
+
+func newTestRepository(t *testing.T) (*Repository[testPartner], *fakeOdoo, *fakeRecords) {
+	t.Helper()
+	srv := newFakeOdoo(t, "s3cret")
+	store := newFakeRecords(
+		map[string]interface{}{"name": "Azure Interior", "email": "azure@example.com", "category_id": []interface{}{int64(1)}},
+		map[string]interface{}{"name": "Deco Addict", "email": false, "category_id": []interface{}{}},
+		map[string]interface{}{"name": "Gemini Furniture", "email": "gemini@example.com", "category_id": []interface{}{}},
+	)
+	srv.handle = store.handle
+	return NewRepository[testPartner](srv.client(t), ModelResPartner), srv, store
+}
+
+// writtenFields returns the sorted fields of each write received by srv.
+func writtenFields(srv *fakeOdoo) [][]string {
+	var writes [][]string
+	for _, call := range srv.received() {
+		if call.Method != "write" {
+			continue
+		}
+		vals, _ := call.Args[1].(map[string]interface{})
+		var fields []string
+		for k := range vals {
+			fields = append(fields, k)
+		}
+		sort.Strings(fields)
+		writes = append(writes, fields)
+	}
+	return writes
+}
+
+func TestRepositorySnapshotsAreKeptPerValue(t *testing.T) {
+	repo, srv, store := newTestRepository(t)
+	ctx := context.Background()
+
+	// Two reads of the same record, edited independently: saving one must not change what
+	// the other compares against.
+	a, err := repo.Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	list, err := repo.Find(ctx, Domain{{"id", "=", 1}})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("Find = %v, %v", list, err)
+	}
+	b := &list[0]
+
+	a.Value.Email = "info@azure.example.com"
+	if got := a.DirtyFields(); !reflect.DeepEqual(got, []string{"email"}) {
+		t.Errorf("DirtyFields = %v, want [email]", got)
+	}
+	if err := repo.Save(ctx, a); err != nil {
+		t.Fatalf("Save(a): %v", err)
+	}
+	b.Value.Name = "Azure Interior SA"
+	if err := repo.Save(ctx, b); err != nil {
+		t.Fatalf("Save(b): %v", err)
+	}
+	if err := repo.Save(ctx, a); err != nil {
+		t.Fatalf("Save(a) again: %v", err)
+	}
+
+	if got, want := writtenFields(srv), [][]string{{"email"}, {"name"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("writes = %v, want %v", got, want)
+	}
+	if rec := store.get(1); rec["email"] != "info@azure.example.com" || rec["name"] != "Azure Interior SA" {
+		t.Errorf("stored record = %v, want both edits", rec)
+	}
+	if a.IsDirty() || b.IsDirty() {
+		t.Error("saved records are still dirty")
+	}
+}
+
+func TestRepositoryCreateThenSave(t *testing.T) {
+	repo, srv, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	rec := &Tracked[testPartner]{Value: testPartner{Name: "Lumber Inc"}}
+	id, err := repo.Create(ctx, rec)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if id == 0 || rec.Value.ID != id {
+		t.Fatalf("Create = %d, record id %d", id, rec.Value.ID)
+	}
+	if rec.IsDirty() {
+		t.Errorf("a created record is dirty: %v", rec.DirtyFields())
+	}
+
+	rec.Value.Email = "lumber@example.com"
+	if err := repo.Save(ctx, rec); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got, want := writtenFields(srv), [][]string{{"email"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("writes = %v, want %v", got, want)
+	}
+
+	// Without snapshot, every writable field is written.
+	manual := &Tracked[testPartner]{Value: testPartner{ID: 2, Name: "Deco Addict"}}
+	if got := manual.DirtyFields(); !reflect.DeepEqual(got, []string{"name", "email", "category_id"}) {
+		t.Errorf("DirtyFields without snapshot = %v", got)
+	}
+}
+
+func TestRepositoryEachAndReset(t *testing.T) {
+	repo, srv, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	var names []string
+	err := repo.Each(ctx, nil, 2, func(rec *Tracked[testPartner]) error {
+		names = append(names, rec.Value.Name)
+		if rec.Value.Email == "" {
+			rec.Value.Email = "unknown@example.com"
+			return repo.Save(ctx, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each: %v", err)
+	}
+	if want := []string{"Azure Interior", "Deco Addict", "Gemini Furniture"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Each visited %v, want %v", names, want)
+	}
+	if got, want := writtenFields(srv), [][]string{{"email"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("writes = %v, want %v", got, want)
+	}
+
+	rec, err := repo.Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	rec.Value.CategoryID = append(rec.Value.CategoryID, 2)
+	rec.Value.Name = "changed"
+	rec.Reset()
+	if rec.IsDirty() || rec.Value.Name != "Azure Interior" || !reflect.DeepEqual(rec.Value.CategoryID, []int64{1}) {
+		t.Errorf("after Reset: %+v, dirty %v", rec.Value, rec.DirtyFields())
+	}
+}
+
+func TestRepositorySaveIfUnchanged(t *testing.T) {
+	repo, srv, store := newTestRepository(t)
+	ctx := context.Background()
+	store.set(1, "write_date", "2024-05-02 10:15:00")
+
+	rec, err := repo.Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	store.set(1, "write_date", "2024-05-02 10:16:00")
+	rec.Value.Name = "stale"
+	if err := repo.SaveIfUnchanged(ctx, rec); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("SaveIfUnchanged of a stale record: error = %v, want ErrConcurrentModification", err)
+	}
+	if writes := writtenFields(srv); len(writes) != 0 {
+		t.Errorf("writes = %v, want none", writes)
+	}
+
+	fresh, err := repo.Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	fresh.Value.Email = "new@example.com"
+	if err := repo.SaveIfUnchanged(ctx, fresh); err != nil {
+		t.Fatalf("SaveIfUnchanged: %v", err)
+	}
+	if got, want := writtenFields(srv), [][]string{{"email"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("writes = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,251 @@
+// godoo/schema.go
+package godoo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// FieldType is the technical type of an Odoo field, as reported by fields_get.
+type FieldType string
+
+// Odoo field types.
+const (
+	FieldChar              FieldType = "char"
+	FieldText              FieldType = "text"
+	FieldHTML              FieldType = "html"
+	FieldInteger           FieldType = "integer"
+	FieldFloat             FieldType = "float"
+	FieldMonetary          FieldType = "monetary"
+	FieldBoolean           FieldType = "boolean"
+	FieldDate              FieldType = "date"
+	FieldDatetime          FieldType = "datetime"
+	FieldSelection         FieldType = "selection"
+	FieldMany2one          FieldType = "many2one"
+	FieldOne2many          FieldType = "one2many"
+	FieldMany2many         FieldType = "many2many"
+	FieldBinary            FieldType = "binary"
+	FieldReference         FieldType = "reference"
+	FieldMany2oneReference FieldType = "many2one_reference"
+	FieldJSON              FieldType = "json"
+	FieldProperties        FieldType = "properties"
+)
+
+// IsRelational reports whether values of the type point to other records.
+func (t FieldType) IsRelational() bool {
+	return t == FieldMany2one || t == FieldOne2many || t == FieldMany2many
+}
+
+// SelectionOption is one allowed value of a selection field.
+type SelectionOption struct {
+	Value string // Technical value stored in the database
+	Label string // Translated label shown to users
+}
+
+// FieldSchema describes one field of a model.
+type FieldSchema struct {
+	Name             string
+	Type             FieldType
+	String           string // Translated field label
+	Help             string
+	Required         bool
+	Readonly         bool
+	Store            bool
+	Relation         Model  // Comodel of relational fields
+	RelationField    string // Inverse field of one2many fields
+	Selection        []SelectionOption
+	Digits           []int // [precision, scale] of float fields, nil when unrestricted
+	CompanyDependent bool
+	Translate        bool
+}
+
+// Schema is the typed result of fields_get for a model.
+type Schema struct {
+	Model  Model
+	Lang   string // Language the labels were read in ("" for the server default)
+	Fields map[string]*FieldSchema
+}
+
+// Field returns the schema of a field, or nil if the model has no such field.
+func (s *Schema) Field(name string) *FieldSchema {
+	return s.Fields[name]
+}
+
+// Names returns the sorted names of all the model's fields.
+func (s *Schema) Names() []string {
+	names := make([]string, 0, len(s.Fields))
+	for name := range s.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stored returns the sorted names of the fields stored in the database.
+func (s *Schema) Stored() Fields {
+	names := Fields{}
+	for _, name := range s.Names() {
+		if s.Fields[name].Store {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// schemaAttributes are the fields_get attributes mapped onto FieldSchema.
+var schemaAttributes = []string{
+	"type", "string", "help", "required", "readonly", "store", "relation", "relation_field",
+	"selection", "digits", "company_dependent", "translate",
+}
+
+// FieldsGetAttributes returns the fields_get attributes that Fields requests and maps onto
+// FieldSchema, for tools that call fields_get themselves (e.g. to save a dump that
+// cmd/godoo-gen reads). The returned slice is a copy.
+func FieldsGetAttributes() []string {
+	return append([]string(nil), schemaAttributes...)
+}
+
+// Fields returns the schema of model via fields_get. Schemas are cached per model and
+// language (the `lang` of the call or default context) for the lifetime of the session, and
+// dropped when the client authenticates again; call ClearSchemaCache after installing or
+// upgrading modules. Only the context of options is used.
+func (c *OdooClient) Fields(ctx context.Context, model Model, options ...*Options) (*Schema, error) {
+	kwargs, err := mergeContext(c.parseOptions(options...), c.defaultContext)
+	if err != nil {
+		return nil, err
+	}
+	lang := contextString(kwargs, "lang")
+	key := string(model) + "|" + lang
+
+	c.sess.mu.Lock()
+	cached := c.sess.schemas[key]
+	c.sess.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	c.logger.Debug("Reading Odoo model fields",
+		zap.String("model", string(model)),
+		zap.String("lang", lang),
+		zap.String("op", "Fields"),
+	)
+
+	// fields_get only takes the context and the attributes; limit, order and the like
+	// would be rejected as unexpected keyword arguments.
+	fieldsKwargs := map[string]interface{}{"attributes": schemaAttributes}
+	if odooCtx, ok := kwargs["context"]; ok {
+		fieldsKwargs["context"] = odooCtx
+	}
+	var raw map[string]interface{}
+	if err := c.executeRPC(ctx, string(model), "fields_get", []interface{}{}, fieldsKwargs, &raw); err != nil {
+		c.logger.Error("Failed to read Odoo model fields",
+			zap.String("model", string(model)),
+			zap.Error(err),
+			zap.String("op", "Fields"),
+		)
+		return nil, err
+	}
+
+	schema, err := parseSchema(model, lang, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	c.sess.mu.Lock()
+	if c.sess.schemas == nil {
+		c.sess.schemas = map[string]*Schema{}
+	}
+	c.sess.schemas[key] = schema
+	c.sess.mu.Unlock()
+	return schema, nil
+}
+
+// ClearSchemaCache drops the cached schemas of the given models, or of every model when
+// called without arguments.
+func (c *OdooClient) ClearSchemaCache(models ...Model) {
+	c.sess.mu.Lock()
+	defer c.sess.mu.Unlock()
+	if len(models) == 0 {
+		c.sess.schemas = nil
+		return
+	}
+	drop := make(map[string]bool, len(models))
+	for _, m := range models {
+		drop[string(m)] = true
+	}
+	for key, schema := range c.sess.schemas {
+		if drop[string(schema.Model)] {
+			delete(c.sess.schemas, key)
+		}
+	}
+}
+
+// SchemaFromFieldsGet builds a Schema from a raw fields_get result, such as one saved as
+// JSON, decoded into generic maps.
+func SchemaFromFieldsGet(model Model, raw map[string]interface{}) (*Schema, error) {
+	return parseSchema(model, "", raw)
+}
+
+// parseSchema converts the raw fields_get dictionary into a Schema.
+func parseSchema(model Model, lang string, raw map[string]interface{}) (*Schema, error) {
+	schema := &Schema{Model: model, Lang: lang, Fields: make(map[string]*FieldSchema, len(raw))}
+	for name, v := range raw {
+		attrs, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: fields_get of '%s': unexpected description %T for field '%s'", ErrInvalidResponse, model, v, name)
+		}
+		f := &FieldSchema{Name: name}
+		f.Type = FieldType(stringAttr(attrs["type"]))
+		f.String = stringAttr(attrs["string"])
+		f.Help = stringAttr(attrs["help"])
+		f.Required, _ = attrs["required"].(bool)
+		f.Readonly, _ = attrs["readonly"].(bool)
+		f.Store, _ = attrs["store"].(bool)
+		f.Relation = Model(stringAttr(attrs["relation"]))
+		f.RelationField = stringAttr(attrs["relation_field"])
+		f.CompanyDependent, _ = attrs["company_dependent"].(bool)
+		f.Translate, _ = attrs["translate"].(bool)
+
+		if options, ok := attrs["selection"].([]interface{}); ok {
+			for _, opt := range options {
+				pair, ok := opt.([]interface{})
+				if !ok || len(pair) != 2 {
+					continue
+				}
+				f.Selection = append(f.Selection, SelectionOption{
+					Value: fmt.Sprint(pair[0]),
+					Label: stringAttr(pair[1]),
+				})
+			}
+		}
+		if digits, ok := attrs["digits"].([]interface{}); ok && len(digits) == 2 {
+			precision, ok1 := toID(digits[0])
+			scale, ok2 := toID(digits[1])
+			if ok1 && ok2 {
+				f.Digits = []int{int(precision), int(scale)}
+			}
+		}
+		schema.Fields[name] = f
+	}
+	return schema, nil
+}
+
+// stringAttr returns v as a string; Odoo sends `false` for unset string attributes.
+func stringAttr(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// contextString returns a string value of the `context` kwarg.
+func contextString(kwargs map[string]interface{}, key string) string {
+	switch ctx := kwargs["context"].(type) {
+	case OdooContext:
+		return stringAttr(ctx[key])
+	case map[string]interface{}:
+		return stringAttr(ctx[key])
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+// godoo/schema_test.go
+package godoo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFieldsSendsOnlyContextAndAttributes(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.handle = func(call fakeCall) (interface{}, error) {
+		return map[string]interface{}{
+			"name": map[string]interface{}{"type": "char", "string": "Name", "required": true},
+		}, nil
+	}
+	c := srv.client(t, WithDefaultContext(OdooContext{"lang": "es_VE"}))
+	ctx := context.Background()
+
+	schema, err := c.Fields(ctx, ModelResPartner, &Options{Limit: 10, Order: "name", Context: OdooContext{"tz": "UTC"}})
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if f, ok := schema.Fields["name"]; !ok || f.Type != "char" || !f.Required {
+		t.Errorf("schema.Fields[name] = %+v, want a required char", f)
+	}
+
+	calls := srv.received()
+	if len(calls) != 1 || calls[0].Method != "fields_get" {
+		t.Fatalf("calls = %+v, want one fields_get", calls)
+	}
+	var keys []string
+	for k := range calls[0].Kwargs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if got := strings.Join(keys, ","); got != "attributes,context" {
+		t.Errorf("fields_get kwargs = %s, want attributes,context", got)
+	}
+	if odooCtx, _ := calls[0].Kwargs["context"].(map[string]interface{}); odooCtx["lang"] != "es_VE" || odooCtx["tz"] != "UTC" {
+		t.Errorf("fields_get context = %v, want the default and call contexts merged", calls[0].Kwargs["context"])
+	}
+}
+
+func TestSchemaCacheIsDroppedOnReauthentication(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.handle = func(call fakeCall) (interface{}, error) {
+		if call.Method == "search" {
+			return []int64{1}, nil
+		}
+		return map[string]interface{}{"name": map[string]interface{}{"type": "char"}}, nil
+	}
+	c := srv.client(t, WithTransport(TransportWebSession))
+	ctx := context.Background()
+	fieldsGets := func() int {
+		n := 0
+		for _, call := range srv.received() {
+			if call.Method == "fields_get" {
+				n++
+			}
+		}
+		return n
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Fields(ctx, ModelResPartner); err != nil {
+			t.Fatalf("Fields: %v", err)
+		}
+	}
+	if n := fieldsGets(); n != 1 {
+		t.Errorf("fields_get calls = %d, want 1 (cached)", n)
+	}
+
+	// The session expires and a call logs in again: the server may have been upgraded.
+	srv.expireSessions()
+	if _, err := c.Search(ctx, ModelResPartner, nil); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if _, err := c.Fields(ctx, ModelResPartner); err != nil {
+		t.Fatalf("Fields after re-authentication: %v", err)
+	}
+	if n := fieldsGets(); n != 2 {
+		t.Errorf("fields_get calls = %d, want 2 (cache dropped on re-authentication)", n)
+	}
+
+	c.Close()
+	if _, err := c.Fields(ctx, ModelResPartner); err != nil {
+		t.Fatalf("Fields after Close: %v", err)
+	}
+	if n := fieldsGets(); n != 3 {
+		t.Errorf("fields_get calls = %d, want 3 (cache dropped by Close)", n)
+	}
+}
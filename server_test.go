@@ -0,0 +1,619 @@
+// godoo/server_test.go
+package godoo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kolo/xmlrpc"
+	"go.uber.org/zap"
+)
+
+// fakeCall is a model method call received by fakeOdoo.
+type fakeCall struct {
+	Model, Method string
+	Args          []interface{}
+	Kwargs        map[string]interface{}
+}
+
+// fakeFault is an Odoo exception raised by a fakeOdoo handler, e.g.
+// &fakeFault{"odoo.exceptions.ValidationError", "bad value"}.
+type fakeFault struct {
+	Name, Message string
+}
+
+func (f *fakeFault) Error() string { return f.Name + ": " + f.Message }
+
+// faultCode is the /xmlrpc/2 fault code Odoo answers err with: 3 for AccessDenied, 4 for
+// AccessError, 2 for the other user errors and 1 for anything else.
+func faultCode(err error) int {
+	fault, ok := err.(*fakeFault)
+	if !ok {
+		return 1
+	}
+	switch fault.Name {
+	case "odoo.exceptions.AccessDenied":
+		return 3
+	case "odoo.exceptions.AccessError":
+		return 4
+	case "odoo.exceptions.UserError", "odoo.exceptions.ValidationError", "odoo.exceptions.MissingError":
+		return 2
+	}
+	return 1
+}
+
+// fakeOdoo is an httptest server speaking the subset of Odoo's protocols used by godoo:
+// XML-RPC and /jsonrpc (common and object services), the web session routes and JSON-2.
+// Model calls are answered by handle; credentials are checked against secret.
+type fakeOdoo struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	secret    string
+	uid       int64
+	version   string
+	logins    int
+	calls     []fakeCall
+	sessions  map[string]bool // valid web session ids
+	nextSID   int
+	handle    func(call fakeCall) (interface{}, error)
+	db        func(method string, args []interface{}) (interface{}, error)
+	onVersion func() // called before answering a version request
+	mux       *http.ServeMux
+}
+
+// newFakeOdoo starts a fake Odoo 17.0 server accepting secret for uid 2.
+func newFakeOdoo(t *testing.T, secret string) *fakeOdoo {
+	t.Helper()
+	f := &fakeOdoo{secret: secret, uid: 2, version: "17.0", sessions: map[string]bool{}, mux: http.NewServeMux()}
+	f.mux.HandleFunc("/jsonrpc", f.serveJSONRPC)
+	f.mux.HandleFunc("/xmlrpc/2/", f.serveXMLRPC)
+	f.mux.HandleFunc("/web/session/authenticate", f.serveWebLogin)
+	f.mux.HandleFunc("/web/dataset/call_kw/", f.serveCallKw)
+	f.mux.HandleFunc("/web/webclient/version_info", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONRPC(w, f.versionInfo(), nil)
+	})
+	f.mux.HandleFunc("/json/2/", f.serveJSON2)
+	f.Server = httptest.NewServer(f.mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// client returns a client of the fake server; opts come after the test defaults.
+func (f *fakeOdoo) client(t *testing.T, opts ...Option) *OdooClient {
+	t.Helper()
+	c, err := New(f.URL, "test", "admin", f.currentSecret(), append([]Option{WithLogger(zap.NewNop())}, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func (f *fakeOdoo) currentSecret() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.secret
+}
+
+// setSecret changes the accepted secret, as a credential rotation would.
+func (f *fakeOdoo) setSecret(secret string) {
+	f.mu.Lock()
+	f.secret = secret
+	f.mu.Unlock()
+}
+
+// expireSessions drops every web session.
+func (f *fakeOdoo) expireSessions() {
+	f.mu.Lock()
+	f.sessions = map[string]bool{}
+	f.mu.Unlock()
+}
+
+func (f *fakeOdoo) loginCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.logins
+}
+
+// received returns the model calls received so far.
+func (f *fakeOdoo) received() []fakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]fakeCall(nil), f.calls...)
+}
+
+func (f *fakeOdoo) versionInfo() map[string]interface{} {
+	f.mu.Lock()
+	onVersion := f.onVersion
+	f.mu.Unlock()
+	if onVersion != nil {
+		onVersion()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return map[string]interface{}{
+		"server_version":      f.version,
+		"server_version_info": []interface{}{17, 0, 0, "final", 0, ""},
+		"server_serie":        f.version,
+		"protocol_version":    1,
+	}
+}
+
+// login checks secret and counts the successful logins.
+func (f *fakeOdoo) login(secret string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if secret != f.secret {
+		return false
+	}
+	f.logins++
+	return true
+}
+
+// dispatch records call and passes it to the handler. The probe of OdooClient.Ping is
+// answered directly and not recorded.
+func (f *fakeOdoo) dispatch(call fakeCall) (interface{}, error) {
+	if call.Model == string(ModelResUsers) && call.Method == "search_count" {
+		return 0, nil
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	handle := f.handle
+	f.mu.Unlock()
+	if handle == nil {
+		return true, nil
+	}
+	return handle(call)
+}
+
+func (f *fakeOdoo) serveJSONRPC(w http.ResponseWriter, r *http.Request) {
+	params, err := decodeParams(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	args, _ := params["args"].([]interface{})
+	result, err := f.service(fmt.Sprint(params["service"]), fmt.Sprint(params["method"]), args)
+	writeJSONRPC(w, result, err)
+}
+
+// service answers the common, object and db services of /jsonrpc and XML-RPC.
+func (f *fakeOdoo) service(service, method string, args []interface{}) (interface{}, error) {
+	switch service {
+	case "common":
+		switch method {
+		case "version":
+			return f.versionInfo(), nil
+		case "login", "authenticate":
+			if len(args) >= 3 && f.login(fmt.Sprint(args[2])) {
+				return f.uid, nil
+			}
+			return false, nil
+		}
+	case "object":
+		if method == "execute_kw" && len(args) >= 6 {
+			if fmt.Sprint(args[2]) != f.currentSecret() {
+				return nil, &fakeFault{"odoo.exceptions.AccessDenied", "Access Denied"}
+			}
+			call := fakeCall{Model: fmt.Sprint(args[3]), Method: fmt.Sprint(args[4])}
+			call.Args, _ = args[5].([]interface{})
+			if len(args) > 6 {
+				call.Kwargs, _ = args[6].(map[string]interface{})
+			}
+			return f.dispatch(call)
+		}
+	case "db":
+		f.mu.Lock()
+		db := f.db
+		f.mu.Unlock()
+		if db != nil {
+			return db(method, args)
+		}
+	}
+	return nil, &fakeFault{"werkzeug.exceptions.NotFound", service + "." + method + " is not supported"}
+}
+
+func (f *fakeOdoo) serveWebLogin(w http.ResponseWriter, r *http.Request) {
+	params, err := decodeParams(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !f.login(fmt.Sprint(params["password"])) {
+		writeJSONRPC(w, nil, &fakeFault{"odoo.exceptions.AccessDenied", "Access Denied"})
+		return
+	}
+	f.mu.Lock()
+	f.nextSID++
+	sid := fmt.Sprintf("sid-%d", f.nextSID)
+	f.sessions[sid] = true
+	f.mu.Unlock()
+	http.SetCookie(w, &http.Cookie{Name: "session_id", Value: sid, Path: "/"})
+	writeJSONRPC(w, map[string]interface{}{"uid": f.uid, "db": "test"}, nil)
+}
+
+// validSession reports whether r carries the cookie of a live web session.
+func (f *fakeOdoo) validSession(r *http.Request) bool {
+	cookie, err := r.Cookie("session_id")
+	if err != nil {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sessions[cookie.Value]
+}
+
+func (f *fakeOdoo) serveCallKw(w http.ResponseWriter, r *http.Request) {
+	params, err := decodeParams(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !f.validSession(r) {
+		writeJSONRPC(w, nil, &fakeFault{jsonRPCSessionExpired, "Session expired"})
+		return
+	}
+	call := fakeCall{Model: fmt.Sprint(params["model"]), Method: fmt.Sprint(params["method"])}
+	call.Args, _ = params["args"].([]interface{})
+	call.Kwargs, _ = params["kwargs"].(map[string]interface{})
+	result, err := f.dispatch(call)
+	writeJSONRPC(w, result, err)
+}
+
+// handleWeb serves an HTTP controller route that requires a web session, redirecting
+// to /web/login like Odoo when the session is missing or expired.
+func (f *fakeOdoo) handleWeb(pattern string, handler http.HandlerFunc) {
+	f.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if !f.validSession(r) {
+			http.Redirect(w, r, "/web/login?redirect="+r.URL.Path, http.StatusSeeOther)
+			return
+		}
+		handler(w, r)
+	})
+}
+
+func (f *fakeOdoo) serveJSON2(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "bearer "+f.currentSecret() {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"name": "odoo.exceptions.AccessDenied", "message": "Invalid apikey"})
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/json/2/"), "/")
+	call := fakeCall{Model: parts[0], Method: parts[len(parts)-1]}
+	body, err := decodeJSON(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	call.Kwargs, _ = body.(map[string]interface{})
+	result, err := f.dispatch(call)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"name": "odoo.exceptions.UserError", "message": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+var xmlrpcMethodName = regexp.MustCompile(`<methodName>([^<]*)</methodName>`)
+
+func (f *fakeOdoo) serveXMLRPC(w http.ResponseWriter, r *http.Request) {
+	raw, _ := io.ReadAll(r.Body)
+	m := xmlrpcMethodName.FindSubmatch(raw)
+	if m == nil {
+		http.Error(w, "no methodName", http.StatusBadRequest)
+		return
+	}
+	// The call parameters are decoded as the items of one array.
+	body := string(raw)
+	body = strings.NewReplacer("<param>", "", "</param>", "").Replace(body)
+	body = strings.Replace(body, "<params>", "<params><param><value><array><data>", 1)
+	body = strings.Replace(body, "</params>", "</data></array></value></param></params>", 1)
+	var args []interface{}
+	if strings.Contains(body, "<params>") {
+		if err := xmlrpc.Response(body).Unmarshal(&args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	for i, a := range args {
+		args[i] = fromXMLRPC(a)
+	}
+
+	service := strings.TrimPrefix(r.URL.Path, "/xmlrpc/2/")
+	result, err := f.service(service, string(m[1]), args)
+	w.Header().Set("Content-Type", "text/xml")
+	if err != nil {
+		fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><fault><value><struct>`+
+			`<member><name>faultCode</name><value><int>%d</int></value></member>`+
+			`<member><name>faultString</name><value><string>%s</string></value></member>`+
+			`</struct></value></fault></methodResponse>`, faultCode(err), err.Error())
+		return
+	}
+	encoded, err := xmlrpc.EncodeMethodCall("", result)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	reply := strings.Replace(string(encoded), "<methodCall><methodName></methodName>", "<methodResponse>", 1)
+	io.WriteString(w, strings.Replace(reply, "</methodCall>", "</methodResponse>", 1))
+}
+
+// fromXMLRPC converts the map[string]interface{} and []interface{} of kolo/xmlrpc values
+// into the shapes decoded from JSON, so handlers see the same values on every transport.
+func fromXMLRPC(v interface{}) interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		for i := range t {
+			t[i] = fromXMLRPC(t[i])
+		}
+	case map[string]interface{}:
+		for k := range t {
+			t[k] = fromXMLRPC(t[k])
+		}
+	}
+	return v
+}
+
+// decodeJSON decodes r with numbers as int64 (or float64), like godoo does.
+func decodeJSON(r io.Reader) (interface{}, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return normalizeJSON(raw), nil
+}
+
+// decodeParams returns the `params` of a JSON-RPC request.
+func decodeParams(r io.Reader) (map[string]interface{}, error) {
+	raw, err := decodeJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	envelope, _ := raw.(map[string]interface{})
+	params, ok := envelope["params"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("no JSON-RPC params")
+	}
+	return params, nil
+}
+
+// writeJSONRPC writes a JSON-RPC answer; a *fakeFault becomes an Odoo error object.
+func writeJSONRPC(w http.ResponseWriter, result interface{}, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	envelope := map[string]interface{}{"jsonrpc": "2.0", "id": 1}
+	if err != nil {
+		name, msg := "odoo.exceptions.UserError", err.Error()
+		if fault, ok := err.(*fakeFault); ok {
+			name, msg = fault.Name, fault.Message
+		}
+		envelope["error"] = map[string]interface{}{
+			"code":    200,
+			"message": "Odoo Server Error",
+			"data":    map[string]interface{}{"name": name, "message": msg},
+		}
+	} else {
+		envelope["result"] = result
+	}
+	json.NewEncoder(w).Encode(envelope)
+}
+
+// fakeRecords is an in-memory model for fakeOdoo handlers, supporting the CRUD methods
+// with domains of [field, op, value] conditions (=, !=, in, >, <), single-field orders,
+// offsets and limits.
+type fakeRecords struct {
+	mu      sync.Mutex
+	records map[int64]map[string]interface{}
+	nextID  int64
+}
+
+func newFakeRecords(records ...map[string]interface{}) *fakeRecords {
+	s := &fakeRecords{records: map[int64]map[string]interface{}{}}
+	for _, rec := range records {
+		s.insert(rec)
+	}
+	return s
+}
+
+// insert stores a copy of rec under a new id. Called with s.mu held, or before use.
+func (s *fakeRecords) insert(rec map[string]interface{}) int64 {
+	s.nextID++
+	stored := map[string]interface{}{"id": s.nextID}
+	for k, v := range rec {
+		if k != "id" {
+			stored[k] = v
+		}
+	}
+	s.records[s.nextID] = stored
+	return s.nextID
+}
+
+// get returns a copy of record id, nil if it does not exist.
+func (s *fakeRecords) get(id int64) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.records[id]
+	if !ok {
+		return nil
+	}
+	out := make(map[string]interface{}, len(rec))
+	for k, v := range rec {
+		out[k] = v
+	}
+	return out
+}
+
+func (s *fakeRecords) set(id int64, field string, value interface{}) {
+	s.mu.Lock()
+	s.records[id][field] = value
+	s.mu.Unlock()
+}
+
+// handle answers search, search_read, search_count, read, create, write and unlink.
+func (s *fakeRecords) handle(call fakeCall) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	arg := func(i int) interface{} {
+		if i < len(call.Args) {
+			return call.Args[i]
+		}
+		return nil
+	}
+	switch call.Method {
+	case "search", "search_count", "search_read":
+		domain, _ := arg(0).([]interface{})
+		ids := s.search(domain)
+		if order, ok := call.Kwargs["order"].(string); ok {
+			s.sortIDs(ids, order)
+		}
+		if offset, ok := call.Kwargs["offset"].(int64); ok && offset > 0 {
+			if offset > int64(len(ids)) {
+				offset = int64(len(ids))
+			}
+			ids = ids[offset:]
+		}
+		if limit, ok := call.Kwargs["limit"].(int64); ok && limit > 0 && int64(len(ids)) > limit {
+			ids = ids[:limit]
+		}
+		switch call.Method {
+		case "search":
+			return ids, nil
+		case "search_count":
+			return len(ids), nil
+		}
+		fields, _ := arg(1).([]interface{})
+		return s.read(ids, fields), nil
+	case "read":
+		fields, _ := arg(1).([]interface{})
+		return s.read(fakeIDs(arg(0)), fields), nil
+	case "create":
+		vals, _ := arg(0).([]interface{})
+		ids := []int64{}
+		for _, v := range vals {
+			rec, _ := v.(map[string]interface{})
+			ids = append(ids, s.insert(rec))
+		}
+		return ids, nil
+	case "write":
+		vals, _ := arg(1).(map[string]interface{})
+		for _, id := range fakeIDs(arg(0)) {
+			for k, v := range vals {
+				s.records[id][k] = v
+			}
+		}
+		return true, nil
+	case "unlink":
+		for _, id := range fakeIDs(arg(0)) {
+			delete(s.records, id)
+		}
+		return true, nil
+	}
+	return nil, &fakeFault{"AttributeError", "no method " + call.Method}
+}
+
+// search returns the ids matching domain, in id order. Called with s.mu held.
+func (s *fakeRecords) search(domain []interface{}) []int64 {
+	ids := []int64{}
+	for id, rec := range s.records {
+		match := true
+		for _, c := range domain {
+			cond, ok := c.([]interface{})
+			if !ok || len(cond) != 3 {
+				continue
+			}
+			v := rec[fmt.Sprint(cond[0])]
+			switch cond[1] {
+			case "=":
+				match = match && fmt.Sprint(v) == fmt.Sprint(cond[2])
+			case "!=":
+				match = match && fmt.Sprint(v) != fmt.Sprint(cond[2])
+			case "in":
+				found := false
+				for _, want := range toAny(cond[2]) {
+					found = found || fmt.Sprint(v) == fmt.Sprint(want)
+				}
+				match = match && found
+			case ">", "<":
+				n, _ := v.(int64)
+				bound, _ := cond[2].(int64)
+				match = match && ((cond[1] == ">" && n > bound) || (cond[1] == "<" && n < bound))
+			}
+		}
+		if match {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// sortIDs sorts ids by a single-field order such as "name desc". Called with s.mu held.
+func (s *fakeRecords) sortIDs(ids []int64, order string) {
+	parts := strings.Fields(order)
+	if len(parts) == 0 {
+		return
+	}
+	desc := len(parts) > 1 && strings.EqualFold(parts[1], "desc")
+	less := func(a, b interface{}) bool {
+		if x, ok := a.(int64); ok {
+			y, _ := b.(int64)
+			return x < y
+		}
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := s.records[ids[i]][parts[0]], s.records[ids[j]][parts[0]]
+		if desc {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+}
+
+// read returns copies of the records with the given fields (all when fields is empty).
+// Called with s.mu held.
+func (s *fakeRecords) read(ids []int64, fields []interface{}) []interface{} {
+	out := []interface{}{}
+	for _, id := range ids {
+		rec, ok := s.records[id]
+		if !ok {
+			continue
+		}
+		row := map[string]interface{}{"id": id}
+		for k, v := range rec {
+			if len(fields) == 0 {
+				row[k] = v
+			}
+		}
+		for _, f := range fields {
+			if v, ok := rec[fmt.Sprint(f)]; ok {
+				row[fmt.Sprint(f)] = v
+			} else {
+				row[fmt.Sprint(f)] = false
+			}
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+func toAny(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+// fakeIDs returns the ids of a call argument, ignoring malformed ones.
+func fakeIDs(v interface{}) []int64 {
+	ids, _ := toIDs(v)
+	return ids
+}
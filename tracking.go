@@ -0,0 +1,295 @@
+// godoo/tracking.go
+package godoo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// Record wraps a record read from Odoo, keeping a snapshot of the values as read and
+// tracking modifications made with Set, so that only changed fields are written back.
+// A Record is not safe for concurrent use.
+type Record struct {
+	Model Model
+	ID    int64
+
+	original map[string]interface{}
+	values   map[string]interface{}
+	types    map[string]FieldType // field types from the model schema, when known
+}
+
+// NewRecord wraps raw record values (as returned by Read) read from model. Field types
+// come from schema when given; without it one2many fields cannot be told apart from
+// many2many fields and removed lines are unlinked instead of deleted.
+func NewRecord(model Model, values map[string]interface{}, schema *Schema) *Record {
+	r := &Record{
+		Model:    model,
+		original: make(map[string]interface{}, len(values)),
+		values:   make(map[string]interface{}, len(values)),
+		types:    map[string]FieldType{},
+	}
+	r.ID, _ = toID(values["id"])
+	for k, v := range values {
+		r.original[k] = v
+		r.values[k] = v
+	}
+	if schema != nil {
+		for name, f := range schema.Fields {
+			r.types[name] = f.Type
+		}
+	}
+	return r
+}
+
+// Get returns the current value of field, nil if it was not read nor set.
+func (r *Record) Get(field string) interface{} {
+	return r.values[field]
+}
+
+// Original returns the value of field as read from Odoo.
+func (r *Record) Original(field string) interface{} {
+	return r.original[field]
+}
+
+// Set changes the value of field. Relational fields accept their write form: an id, a
+// Many2One or false for many2one fields, and []int64 (the new list of ids) or a list of
+// X2ManyCommand for x2many fields.
+func (r *Record) Set(field string, value interface{}) {
+	r.values[field] = value
+}
+
+// DirtyFields returns the sorted names of the fields whose value differs from the snapshot.
+func (r *Record) DirtyFields() []string {
+	var dirty []string
+	for name, value := range r.values {
+		if original, ok := r.original[name]; !ok || !sameFieldValue(original, value) {
+			dirty = append(dirty, name)
+		}
+	}
+	sort.Strings(dirty)
+	return dirty
+}
+
+// IsDirty reports whether any field was changed.
+func (r *Record) IsDirty() bool {
+	return len(r.DirtyFields()) > 0
+}
+
+// Diff returns the minimal Data to write the changes: the changed fields only, with
+// many2one values reduced to their id and x2many lists turned into link/unlink commands
+// (delete for removed one2many lines).
+func (r *Record) Diff() Data {
+	data := Data{}
+	for _, name := range r.DirtyFields() {
+		data[name] = r.writeValue(name)
+	}
+	return data
+}
+
+// Reset discards the changes made since the record was read or last saved.
+func (r *Record) Reset() {
+	r.values = make(map[string]interface{}, len(r.original))
+	for k, v := range r.original {
+		r.values[k] = v
+	}
+}
+
+// markClean makes the current values the new snapshot after a successful write. Fields set
+// with raw x2many commands are dropped, since the resulting ids are only known to Odoo.
+func (r *Record) markClean() {
+	for name, value := range r.values {
+		if isCommandList(value) {
+			delete(r.values, name)
+			delete(r.original, name)
+			continue
+		}
+		r.original[name] = value
+	}
+}
+
+// writeValue converts the current value of field into its write form.
+func (r *Record) writeValue(name string) interface{} {
+	value := rpcValue(r.values[name])
+	if isCommandList(value) {
+		return value
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		if id, _ := many2oneID(v); len(v) == 2 && id != 0 && isString(v[1]) {
+			return id
+		}
+	}
+
+	newIDs, err := toIDs(value)
+	oldIDs, oldErr := toIDs(r.original[name])
+	if err != nil || oldErr != nil || !r.isX2Many(name) {
+		return value
+	}
+
+	old := make(map[int64]bool, len(oldIDs))
+	for _, id := range oldIDs {
+		old[id] = true
+	}
+	cur := make(map[int64]bool, len(newIDs))
+	commands := []interface{}{}
+	for _, id := range newIDs {
+		cur[id] = true
+		if !old[id] {
+			commands = append(commands, X2ManyLink(id))
+		}
+	}
+	for _, id := range oldIDs {
+		if !cur[id] {
+			if r.types[name] == FieldOne2many {
+				commands = append(commands, X2ManyDelete(id))
+			} else {
+				commands = append(commands, X2ManyUnlink(id))
+			}
+		}
+	}
+	return commands
+}
+
+// isX2Many reports whether field holds a list of ids, from the schema when known and
+// otherwise from the value read.
+func (r *Record) isX2Many(name string) bool {
+	if t, ok := r.types[name]; ok {
+		return t == FieldOne2many || t == FieldMany2many
+	}
+	switch v := r.original[name].(type) {
+	case []int64:
+		return true
+	case []interface{}:
+		_, err := toIDs(v)
+		return err == nil
+	}
+	return false
+}
+
+// sameFieldValue compares a value read from Odoo with a value set by the caller, treating
+// many2one pairs as their id, id lists as sets and numbers by value.
+func sameFieldValue(original, value interface{}) bool {
+	if isCommandList(value) {
+		return false
+	}
+	value = rpcValue(value)
+	original = rpcValue(original)
+	// Odoo reads empty fields as false.
+	if isEmptyValue(original) && isEmptyValue(value) {
+		return true
+	}
+	if pair, ok := original.([]interface{}); ok && len(pair) == 2 && isString(pair[1]) {
+		original, _ = many2oneID(pair)
+	}
+	if pair, ok := value.([]interface{}); ok && len(pair) == 2 && isString(pair[1]) {
+		value, _ = many2oneID(pair)
+	}
+
+	if a, ok := toID(original); ok {
+		b, ok := toID(value)
+		if !ok {
+			if f, isFloat := value.(float64); isFloat {
+				return float64(a) == f
+			}
+		}
+		return ok && a == b
+	}
+	if a, err := toIDs(original); err == nil && a != nil {
+		b, err := toIDs(value)
+		if err != nil {
+			return false
+		}
+		return sameIDs(reflect.ValueOf(a), reflect.ValueOf(b))
+	}
+	return reflect.DeepEqual(original, value)
+}
+
+// isCommandList reports whether v is a list of x2many commands ([[4, id, 0], ...]), either
+// as []interface{} or as []X2ManyCommand.
+func isCommandList(v interface{}) bool {
+	switch list := v.(type) {
+	case []X2ManyCommand:
+		return len(list) > 0
+	case []interface{}:
+		if len(list) == 0 {
+			return false
+		}
+		_, isCommand := list[0].([]interface{})
+		return isCommand
+	}
+	return false
+}
+
+// isEmptyValue reports whether v is one of the forms of an empty field: nil, false or "".
+func isEmptyValue(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !t
+	case string:
+		return t == ""
+	}
+	return false
+}
+
+func isString(v interface{}) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+// ReadRecords reads records wrapped for change tracking. The model schema is fetched
+// (and cached, see Fields) to generate the right x2many commands.
+func (c *OdooClient) ReadRecords(ctx context.Context, model Model, ids []int64, fields Fields, options ...*Options) ([]*Record, error) {
+	schema, err := c.Fields(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := c.Read(ctx, model, ids, fields, options...)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*Record, len(raw))
+	for i, values := range raw {
+		records[i] = NewRecord(model, values, schema)
+	}
+	return records, nil
+}
+
+// ReadRecord reads one record wrapped for change tracking, or returns ErrRecordNotFound.
+func (c *OdooClient) ReadRecord(ctx context.Context, model Model, id int64, fields Fields, options ...*Options) (*Record, error) {
+	records, err := c.ReadRecords(ctx, model, []int64{id}, fields, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: for model '%s' with ID %v", ErrRecordNotFound, string(model), id)
+	}
+	return records[0], nil
+}
+
+// SaveRecord writes the changed fields of rec (see Record.Diff) and makes them its new
+// snapshot. It returns false without calling Odoo when nothing changed.
+func (c *OdooClient) SaveRecord(ctx context.Context, rec *Record, options ...*Options) (bool, error) {
+	if rec.ID == 0 {
+		return false, fmt.Errorf("godoo: cannot save a %s record without id", rec.Model)
+	}
+	diff := rec.Diff()
+	if len(diff) == 0 {
+		c.logger.Debug("No changes to save",
+			zap.String("model", string(rec.Model)),
+			zap.Int64("id", rec.ID),
+			zap.String("op", "SaveRecord"),
+		)
+		return false, nil
+	}
+	if _, err := c.Update(ctx, rec.Model, []int64{rec.ID}, diff, options...); err != nil {
+		return false, err
+	}
+	rec.markClean()
+	return true, nil
+}
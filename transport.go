@@ -0,0 +1,140 @@
+// godoo/transport.go
+package godoo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/kolo/xmlrpc"
+)
+
+// Transport identifies the wire protocol used to call Odoo model methods.
+type Transport string
+
+const (
+	// TransportXMLRPC calls `execute_kw` on /xmlrpc/2/object (all Odoo versions).
+	TransportXMLRPC Transport = "xmlrpc"
+	// TransportJSON2 calls the per-model HTTP endpoints /json/2/<model>/<method>
+	// introduced with Odoo 17+, authenticated with a bearer API key.
+	TransportJSON2 Transport = "json2"
+	// TransportWebSession logs in through /web/session/authenticate and calls model
+	// methods via /web/dataset/call_kw, keeping the `session_id` cookie in a cookie jar.
+	// Useful behind reverse proxies that only expose the /web/* routes.
+	TransportWebSession Transport = "web"
+	// TransportJSONRPC calls `execute_kw` through the /jsonrpc endpoint. It accepts the same
+	// credentials as XML-RPC and honours ctx cancellation while the request is in flight.
+	TransportJSONRPC Transport = "jsonrpc"
+)
+
+// WithTransport selecciona el protocolo usado para invocar métodos de modelos.
+// Por defecto se usa TransportXMLRPC, salvo con WithBearerToken (TransportJSON2).
+func WithTransport(t Transport) Option {
+	return func(c *OdooClient) {
+		c.transport = t
+	}
+}
+
+// rpcCaller executes model methods over an authenticated connection.
+// Each Transport provides its own implementation; OdooClient only talks to this interface.
+type rpcCaller interface {
+	// call invokes `method` on `model` with positional `args` and keyword `kwargs`,
+	// decoding the result into `reply`. It must honour ctx cancellation.
+	call(ctx context.Context, model, method string, args []interface{}, kwargs map[string]interface{}, reply interface{}) error
+	// close releases any resources (codecs) held by the caller. It must not close the idle
+	// connections of the HTTP transport, which may be shared with other clients.
+	close()
+}
+
+// xmlrpcCaller implements rpcCaller on top of Odoo's `execute_kw` XML-RPC method.
+type xmlrpcCaller struct {
+	client *xmlrpc.Client
+	db     string
+	uid    int64
+	secret string
+
+	mu       sync.Mutex
+	inflight int  // calls still using client, abandoned ones included
+	closed   bool // close was called; client is closed once inflight drops to 0
+}
+
+// call runs `execute_kw` in a goroutine so the blocking XML-RPC call can be abandoned
+// when ctx is done ('kolo/xmlrpc' does not accept a context itself).
+func (x *xmlrpcCaller) call(ctx context.Context, model, method string, args []interface{}, kwargs map[string]interface{}, reply interface{}) error {
+	// Odoo's execute_kw expects (db, uid, password, model, method, args[], kwargs{})
+	// `execute_kw` always expects a kwargs dictionary, even if empty.
+	if kwargs == nil {
+		kwargs = map[string]interface{}{}
+	}
+	if args == nil {
+		args = []interface{}{}
+	}
+	callArgs := []interface{}{x.db, x.uid, x.secret, model, method, args, kwargs}
+
+	x.mu.Lock()
+	if x.closed {
+		x.mu.Unlock()
+		// A concurrent call dropped the session after this one picked it up.
+		return fmt.Errorf("%w: XML-RPC session closed by re-authentication", ErrSessionExpired)
+	}
+	x.inflight++
+	x.mu.Unlock()
+	return callXMLRPC(ctx, x.client, "execute_kw", callArgs, reply, x.release)
+}
+
+// release ends a call, closing the client if close was called meanwhile.
+func (x *xmlrpcCaller) release() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.inflight--
+	if x.closed && x.inflight == 0 {
+		x.client.Close()
+	}
+}
+
+// callXMLRPC performs a blocking XML-RPC call, abandoning it when ctx is done since
+// kolo/xmlrpc does not take a context. done, if not nil, runs once the call has returned,
+// even when it was abandoned.
+func callXMLRPC(ctx context.Context, client *xmlrpc.Client, serviceMethod string, args interface{}, reply interface{}, done func()) error {
+	callChan := make(chan error, 1)
+	go func() {
+		if done != nil {
+			defer done()
+		}
+		callChan <- client.Call(serviceMethod, args, reply)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-callChan:
+		return err
+	}
+}
+
+// close closes the client once no call uses it: kolo's Close makes the requests still in
+// flight block forever, and concurrent calls may still hold this caller.
+func (x *xmlrpcCaller) close() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.closed = true
+	if x.inflight == 0 {
+		x.client.Close()
+	}
+}
+
+// closeXMLRPC returns a done func for callXMLRPC that closes a single-use client.
+func closeXMLRPC(client *xmlrpc.Client) func() {
+	return func() { client.Close() }
+}
+
+// newXMLRPCClient creates a kolo/xmlrpc client for endpoint on transport. kolo's Close
+// closes the idle connections of an *http.Transport, which may be shared (e.g. by the
+// clients of a Manager), so the transport is handed over as a plain RoundTripper.
+func newXMLRPCClient(endpoint string, transport http.RoundTripper) (*xmlrpc.Client, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return xmlrpc.NewClient(endpoint, struct{ http.RoundTripper }{transport})
+}
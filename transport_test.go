@@ -0,0 +1,104 @@
+// godoo/transport_test.go
+package godoo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTransportsCallModelMethods(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"xmlrpc password", nil},
+		{"xmlrpc api key", []Option{WithAPIKey("s3cret")}},
+		{"xmlrpc known uid", []Option{WithAPIKey("s3cret"), WithUID(2)}},
+		{"jsonrpc", []Option{WithTransport(TransportJSONRPC)}},
+		{"web session", []Option{WithTransport(TransportWebSession)}},
+		{"json2 bearer", []Option{WithBearerToken("s3cret")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeOdoo(t, "s3cret")
+			srv.handle = func(call fakeCall) (interface{}, error) {
+				return []int64{7, 9}, nil
+			}
+			c := srv.client(t, tt.opts...)
+			defer c.Close()
+
+			ids, err := c.Search(context.Background(), ModelResPartner, Domain{{"is_company", "=", true}}, Limit(5))
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if !reflect.DeepEqual(ids, []int64{7, 9}) {
+				t.Errorf("Search = %v, want [7 9]", ids)
+			}
+
+			calls := srv.received()
+			if len(calls) != 1 || calls[0].Model != "res.partner" || calls[0].Method != "search" {
+				t.Fatalf("received %+v, want one res.partner.search", calls)
+			}
+			wantDomain := []interface{}{[]interface{}{"is_company", "=", true}}
+			domain := calls[0].Kwargs["domain"] // JSON-2 names every parameter
+			if len(calls[0].Args) > 0 {
+				domain = calls[0].Args[0]
+			}
+			if !reflect.DeepEqual(domain, wantDomain) {
+				t.Errorf("domain = %#v, want %#v", domain, wantDomain)
+			}
+			if limit := calls[0].Kwargs["limit"]; limit != int64(5) {
+				t.Errorf("limit = %#v, want 5", limit)
+			}
+		})
+	}
+}
+
+func TestTransportsRejectWrongSecret(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"xmlrpc", nil},
+		{"jsonrpc", []Option{WithTransport(TransportJSONRPC)}},
+		{"web session", []Option{WithTransport(TransportWebSession)}},
+		{"json2", []Option{WithBearerToken("wrong")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeOdoo(t, "s3cret")
+			c := srv.client(t, append([]Option{WithCredentialProvider(CredentialPassword, StaticCredential("wrong"))}, tt.opts...)...)
+			err := c.Ping(context.Background())
+			if !errors.Is(err, ErrAuthenticationFailed) {
+				t.Errorf("Ping error = %v, want ErrAuthenticationFailed", err)
+			}
+		})
+	}
+}
+
+func TestCallMethodSpreadsArgsAndKwargs(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.handle = func(call fakeCall) (interface{}, error) { return true, nil }
+	c := srv.client(t, WithTransport(TransportJSONRPC))
+
+	_, err := c.CallMethod(context.Background(), "res.partner", "check_access_rights", []string{"read"}, map[string]interface{}{"raise_exception": false})
+	if err != nil {
+		t.Fatalf("CallMethod: %v", err)
+	}
+	calls := srv.received()
+	if len(calls) != 1 {
+		t.Fatalf("received %d calls, want 1", len(calls))
+	}
+	if !reflect.DeepEqual(calls[0].Args, []interface{}{"read"}) {
+		t.Errorf("args = %#v, want [read]", calls[0].Args)
+	}
+	if calls[0].Kwargs["raise_exception"] != false {
+		t.Errorf("kwargs = %#v, want raise_exception=false", calls[0].Kwargs)
+	}
+
+	if _, err := c.CallMethod(context.Background(), "res.partner", "x", []interface{}{}, map[string]interface{}{}, 3); err == nil {
+		t.Error("CallMethod accepted three execute_kw values")
+	}
+}
@@ -2,6 +2,12 @@ package godoo
 
 // types.go
 
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
 // Model represents an Odoo model name.
 // This type provides compile-time safety and enables autocompletion
 // in IDEs when using predefined model constants.
@@ -56,6 +62,8 @@ const (
 	ModelIrModel            Model = "ir.model"              // Odoo Models Metadata (used for introspection)
 	ModelIrAttachment       Model = "ir.attachment"         // File Attachments linked to records
 	ModelIrActionsActWindow Model = "ir.actions.act_window" // Window Actions (how views are opened)
+	ModelIrActionsReport    Model = "ir.actions.report"     // Report Actions (QWeb PDF/HTML reports)
+	ModelIrModelData        Model = "ir.model.data"         // External Identifiers (XML IDs)
 	ModelIrSequence         Model = "ir.sequence"           // Document Sequencing (e.g., for invoice numbers)
 
 	// Messaging & Activity Models
@@ -109,7 +117,11 @@ func (d Domain) ToRPC() []interface{} { // ¡Aquí está el cambio clave!
 				rpcDomain = append(rpcDomain, cond)
 			}
 		} else {
-			// For standard conditions (e.g., {"field", "=", "value"}), append the entire slice.
+			// For standard conditions (e.g., {"field", "=", "value"}), append the entire slice,
+			// converting typed values such as godoo.Date into their RPC form.
+			if len(cond) == 3 {
+				cond = DomainCondition{cond[0], cond[1], rpcValue(cond[2])}
+			}
 			rpcDomain = append(rpcDomain, cond)
 		}
 	}
@@ -122,15 +134,98 @@ func (d Domain) ToRPC() []interface{} { // ¡Aquí está el cambio clave!
 type Fields []string
 
 // ToRPC converts the Fields type to a []string suitable for Odoo RPC calls.
+// A nil Fields yields an empty list (all fields) rather than a JSON null.
 func (f Fields) ToRPC() []string {
+	if f == nil {
+		return []string{}
+	}
 	return []string(f)
 }
 
+// Many2One represents the value of a many2one field: Odoo reads it as [id, "display name"]
+// and `false` when empty (ID 0).
+type Many2One struct {
+	ID   int64
+	Name string
+}
+
+// IsSet reports whether the field points to a record.
+func (m Many2One) IsSet() bool {
+	return m.ID != 0
+}
+
+// ToRPC converts the Many2One to the value Odoo expects when writing: the id, or false.
+func (m Many2One) ToRPC() interface{} {
+	if m.ID == 0 {
+		return false
+	}
+	return m.ID
+}
+
 // OdooContext represents the 'context' dictionary passed as an option
 // in Odoo RPC calls. It allows custom key-value pairs that influence
 // Odoo's server-side logic (e.g., language, timezone, active_test).
 type OdooContext map[string]interface{}
 
+// clone returns a shallow copy of the context (nil for an empty one).
+func (oc OdooContext) clone() OdooContext {
+	if len(oc) == 0 {
+		return nil
+	}
+	out := make(OdooContext, len(oc))
+	for k, v := range oc {
+		out[k] = v
+	}
+	return out
+}
+
+// mergeContext returns the keyword arguments with `defaults` merged into their `context`
+// entry. Keys already present in the call's own context take precedence. The call's context
+// may be any map with string keys (OdooContext, Data, map[string]string...); anything else
+// is an error. The input map is never modified.
+func mergeContext(kwargs map[string]interface{}, defaults OdooContext) (map[string]interface{}, error) {
+	callCtx, hasCtx := kwargs["context"]
+	if hasCtx && callCtx == nil {
+		hasCtx = false
+	}
+	if len(defaults) == 0 && !hasCtx {
+		return kwargs, nil
+	}
+
+	merged := make(OdooContext, len(defaults))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	if hasCtx {
+		switch cc := callCtx.(type) {
+		case OdooContext:
+			for k, v := range cc {
+				merged[k] = v
+			}
+		case map[string]interface{}:
+			for k, v := range cc {
+				merged[k] = v
+			}
+		default:
+			rv := reflect.ValueOf(callCtx)
+			if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("godoo: the call context must be a map with string keys, got %T", callCtx)
+			}
+			iter := rv.MapRange()
+			for iter.Next() {
+				merged[iter.Key().String()] = iter.Value().Interface()
+			}
+		}
+	}
+
+	out := make(map[string]interface{}, len(kwargs)+1)
+	for k, v := range kwargs {
+		out[k] = v
+	}
+	out["context"] = merged
+	return out, nil
+}
+
 // Options represents common keyword arguments for Odoo RPC methods.
 // This struct simplifies specifying common options like limit, offset, order,
 // and the Odoo-specific 'context'. For less common options, the 'Extra' map can be used.
@@ -181,22 +276,45 @@ func (o *Options) ToRPC() map[string]interface{} {
 type Data map[string]interface{}
 
 // ToRPC converts the Data type to a map[string]interface{} suitable for Odoo RPC calls.
-// This method is provided for consistency with other ToRPC methods, though
-// a direct type assertion is often sufficient.
+// Values implementing Marshaler (Date, DateTime, Many2One...) and time.Time values are
+// converted to the form Odoo expects.
 func (d Data) ToRPC() map[string]interface{} {
-	return map[string]interface{}(d)
+	if d == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(d))
+	for k, v := range d {
+		out[k] = rpcValue(v)
+	}
+	return out
+}
+
+// rpcValue converts a value given in Data or a Domain into its RPC form.
+func rpcValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case Marshaler:
+		if out, err := t.MarshalOdoo(); err == nil {
+			return out
+		}
+	case time.Time:
+		return rpcValue(DateTimeOf(t))
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, item := range t {
+			out[i] = rpcValue(item)
+		}
+		return out
+	}
+	return v
 }
 
 // parseOptions converts a slice of Options pointers into a single map[string]interface{}
-// suitable for Odoo RPC keyword arguments (kwargs). It handles merging multiple options.
+// suitable for Odoo RPC keyword arguments (kwargs). It handles merging multiple options
+// with the semantics of MergeOptions, so call sites can compose helpers such as
+// `godoo.Limit(10), godoo.Lang("es_VE")`.
 func (c *OdooClient) parseOptions(options ...*Options) map[string]interface{} {
-	if len(options) == 0 || options[0] == nil {
+	if len(options) == 0 {
 		return map[string]interface{}{}
 	}
-
-	// Start with the first options map.
-	// Subsequent options will override or merge with previous ones.
-	// For simplicity, we just use the first options struct provided.
-	// A more complex merger could iterate if multiple *Options were passed.
-	return options[0].ToRPC()
+	return MergeOptions(options...).ToRPC()
 }
@@ -0,0 +1,72 @@
+// godoo/types_test.go
+package godoo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMergeContext(t *testing.T) {
+	defaults := OdooContext{"lang": "en_US", "tz": "UTC"}
+	tests := []struct {
+		name    string
+		callCtx interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{"no call context", nil, map[string]interface{}{"lang": "en_US", "tz": "UTC"}, false},
+		{"OdooContext", OdooContext{"lang": "es_VE"}, map[string]interface{}{"lang": "es_VE", "tz": "UTC"}, false},
+		{"map[string]interface{}", map[string]interface{}{"active_test": false}, map[string]interface{}{"lang": "en_US", "tz": "UTC", "active_test": false}, false},
+		{"Data", Data{"lang": "fr_FR"}, map[string]interface{}{"lang": "fr_FR", "tz": "UTC"}, false},
+		{"map[string]string", map[string]string{"tz": "America/Caracas"}, map[string]interface{}{"lang": "en_US", "tz": "America/Caracas"}, false},
+		{"not a map", "lang=es_VE", nil, true},
+		{"non-string keys", map[int]string{1: "es_VE"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kwargs := map[string]interface{}{"limit": 5}
+			if tt.callCtx != nil {
+				kwargs["context"] = tt.callCtx
+			}
+			got, err := mergeContext(kwargs, defaults)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("mergeContext() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeContext(): %v", err)
+			}
+			if !reflect.DeepEqual(map[string]interface{}(got["context"].(OdooContext)), tt.want) {
+				t.Errorf("context = %v, want %v", got["context"], tt.want)
+			}
+			if got["limit"] != 5 {
+				t.Errorf("limit = %v, want 5", got["limit"])
+			}
+			if tt.callCtx != nil && !reflect.DeepEqual(kwargs["context"], tt.callCtx) {
+				t.Errorf("the caller's kwargs were modified: %v", kwargs)
+			}
+		})
+	}
+}
+
+func TestCallContextOfAnyMapTypeReachesServer(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	var got interface{}
+	srv.handle = func(call fakeCall) (interface{}, error) {
+		got = call.Kwargs["context"]
+		return []int64{}, nil
+	}
+	c := srv.client(t, WithDefaultContext(OdooContext{"tz": "UTC"}))
+
+	opts := &Options{Extra: map[string]interface{}{"context": map[string]string{"lang": "es_VE"}}}
+	if _, err := c.Search(context.Background(), ModelResPartner, nil, opts); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	want := map[string]interface{}{"lang": "es_VE", "tz": "UTC"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("context = %v, want %v", got, want)
+	}
+}
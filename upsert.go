@@ -0,0 +1,265 @@
+// godoo/upsert.go
+package godoo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// UpsertOutcome tells what Upsert did with a row.
+type UpsertOutcome string
+
+const (
+	UpsertCreated   UpsertOutcome = "created"   // No record matched the key; one was created
+	UpsertUpdated   UpsertOutcome = "updated"   // The matching record was written
+	UpsertUnchanged UpsertOutcome = "unchanged" // The matching record already had the row's values
+)
+
+// UpsertResult is the outcome of one upserted row.
+type UpsertResult struct {
+	ID      int64
+	Outcome UpsertOutcome
+}
+
+// Upsert creates the record of model whose key fields equal those in data, or writes data to
+// it when it exists, e.g. a product.template keyed by default_code or a res.partner keyed by
+// vat. The key fields must be present in data. Only the fields whose value differs are
+// written; when none does, nothing is written and the outcome is UpsertUnchanged. A key
+// matching several records is an error.
+//
+// Archived records do not match unless the options disable active_test (ActiveTest(false)).
+func (c *OdooClient) Upsert(ctx context.Context, model Model, key Fields, data Data, options ...*Options) (UpsertResult, error) {
+	results, err := c.UpsertMany(ctx, model, key, []Data{data}, options...)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	return results[0], nil
+}
+
+// UpsertWhere is Upsert with the existing record selected by domain instead of key fields.
+func (c *OdooClient) UpsertWhere(ctx context.Context, model Model, domain Domain, data Data, options ...*Options) (UpsertResult, error) {
+	var existing []map[string]interface{}
+	err := c.executeRPC(ctx, string(model), "search_read", []interface{}{domain.ToRPC(), dataFields(data).ToRPC()}, c.parseOptions(append(options[:len(options):len(options)], Limit(2))...), &existing)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	switch len(existing) {
+	case 0:
+		id, err := c.CreateOne(ctx, model, data, options...)
+		if err != nil {
+			return UpsertResult{}, err
+		}
+		return UpsertResult{ID: id, Outcome: UpsertCreated}, nil
+	case 1:
+		id, _ := toID(existing[0]["id"])
+		changes := changedData(existing[0], data)
+		if len(changes) == 0 {
+			return UpsertResult{ID: id, Outcome: UpsertUnchanged}, nil
+		}
+		if _, err := c.Update(ctx, model, []int64{id}, changes, options...); err != nil {
+			return UpsertResult{}, err
+		}
+		return UpsertResult{ID: id, Outcome: UpsertUpdated}, nil
+	}
+	return UpsertResult{}, fmt.Errorf("godoo: upsert domain matches several %s records", model)
+}
+
+// UpsertMany upserts rows by their key fields (see Upsert) in few calls: existing records
+// are searched with one search_read, new rows are created with a single create call, and
+// changed rows are written with one write per distinct set of changes. Results are in the
+// order of rows. Rows are validated before anything is written: a missing or empty key
+// field, two rows with the same key, or a key matching several records fail the whole
+// batch. When a create or write fails, the results are returned along with the error: rows
+// that were not (or not fully) applied have a zero Outcome, and ID 0 if not created.
+func (c *OdooClient) UpsertMany(ctx context.Context, model Model, key Fields, rows []Data, options ...*Options) ([]UpsertResult, error) {
+	c.logger.Debug("Performing Odoo upsert",
+		zap.String("model", string(model)),
+		zap.Any("key", key),
+		zap.Int("rows_count", len(rows)),
+		zap.String("op", "UpsertMany"),
+	)
+	if len(key) == 0 {
+		return nil, fmt.Errorf("godoo: upsert on %s needs at least one key field", model)
+	}
+	results := make([]UpsertResult, len(rows))
+	if len(rows) == 0 {
+		return results, nil
+	}
+
+	keys := make([]string, len(rows))
+	seen := make(map[string]int, len(rows))
+	fields := Fields{}
+	for i, row := range rows {
+		k, err := upsertKey(key, row)
+		if err != nil {
+			return nil, fmt.Errorf("godoo: upsert row %d: %w", i, err)
+		}
+		if j, dup := seen[k]; dup {
+			return nil, fmt.Errorf("godoo: upsert rows %d and %d have the same key %s", j, i, k)
+		}
+		seen[k] = i
+		keys[i] = k
+		fields = append(fields, dataFields(row)...)
+	}
+
+	var existing []map[string]interface{}
+	err := c.executeRPC(ctx, string(model), "search_read", []interface{}{upsertDomain(key, rows).ToRPC(), uniqueFields(fields).ToRPC()}, c.parseOptions(options...), &existing)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]map[string]interface{}, len(existing))
+	for _, record := range existing {
+		k, err := upsertKey(key, record)
+		if err != nil {
+			continue
+		}
+		if _, dup := byKey[k]; dup {
+			return nil, fmt.Errorf("godoo: upsert key %s matches several %s records", k, model)
+		}
+		byKey[k] = record
+	}
+
+	var creates []Data
+	var createRows []int
+	updates := make(map[int64]Data)
+	updateRows := make(map[int64]int) // Outcome is only set once the write succeeded
+	for i, row := range rows {
+		record, ok := byKey[keys[i]]
+		if !ok {
+			creates = append(creates, row)
+			createRows = append(createRows, i)
+			continue
+		}
+		id, _ := toID(record["id"])
+		results[i].ID = id
+		if changes := changedData(record, row); len(changes) > 0 {
+			updates[id] = changes
+			updateRows[id] = i
+		} else {
+			results[i].Outcome = UpsertUnchanged
+		}
+	}
+
+	if len(creates) > 0 {
+		ids, err := c.Create(ctx, model, creates, options...)
+		if err != nil {
+			return results, err
+		}
+		if len(ids) != len(creates) {
+			return results, fmt.Errorf("%w: create returned %d ids for %d records", ErrInvalidResponse, len(ids), len(creates))
+		}
+		for j, i := range createRows {
+			results[i] = UpsertResult{ID: ids[j], Outcome: UpsertCreated}
+		}
+	}
+	for _, group := range groupWrites(updates) {
+		if _, err := c.Update(ctx, model, group.ids, group.data, options...); err != nil {
+			return results, err
+		}
+		for _, id := range group.ids {
+			results[updateRows[id]].Outcome = UpsertUpdated
+		}
+	}
+
+	c.logger.Info("Odoo upsert completed",
+		zap.String("model", string(model)),
+		zap.Int("created", len(creates)),
+		zap.Int("updated", len(updates)),
+		zap.Int("unchanged", len(rows)-len(creates)-len(updates)),
+		zap.String("op", "UpsertMany"),
+	)
+	return results, nil
+}
+
+// upsertKey returns a canonical encoding of the key fields of a row or record. Empty values
+// (nil, false, "") are rejected: Odoo reads them all as false, so they cannot identify a
+// record and would never match the row they came from.
+func upsertKey(key Fields, values map[string]interface{}) (string, error) {
+	parts := make([]interface{}, len(key))
+	for i, name := range key {
+		v, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("key field '%s' is missing", name)
+		}
+		v = rpcValue(v)
+		if isEmptyValue(v) {
+			return "", fmt.Errorf("key field '%s' is empty", name)
+		}
+		// A many2one key is read as [id, "name"].
+		if pair, ok := v.([]interface{}); ok && len(pair) == 2 && isString(pair[1]) {
+			v, _ = many2oneID(pair)
+		}
+		if id, ok := toID(v); ok {
+			v = id
+		}
+		parts[i] = v
+	}
+	raw, err := json.Marshal(parts)
+	if err != nil {
+		return "", fmt.Errorf("cannot encode key: %w", err)
+	}
+	return string(raw), nil
+}
+
+// upsertDomain matches the records whose key fields equal those of any row.
+func upsertDomain(key Fields, rows []Data) Domain {
+	if len(key) == 1 {
+		values := make([]interface{}, len(rows))
+		for i, row := range rows {
+			values[i] = rpcValue(row[key[0]])
+		}
+		return Domain{{key[0], "in", values}}
+	}
+	var domain Domain
+	for i := 1; i < len(rows); i++ {
+		domain = append(domain, DomainCondition{"|"})
+	}
+	for _, row := range rows {
+		for i := 1; i < len(key); i++ {
+			domain = append(domain, DomainCondition{"&"})
+		}
+		for _, name := range key {
+			domain = append(domain, DomainCondition{name, "=", row[name]})
+		}
+	}
+	return domain
+}
+
+// changedData returns the entries of data whose value differs from the record's.
+func changedData(record map[string]interface{}, data Data) Data {
+	changes := Data{}
+	for name, value := range data {
+		if current, ok := record[name]; !ok || !sameFieldValue(current, value) {
+			changes[name] = value
+		}
+	}
+	return changes
+}
+
+// dataFields returns the field names of data, sorted.
+func dataFields(data Data) Fields {
+	fields := make(Fields, 0, len(data))
+	for name := range data {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// uniqueFields returns fields without duplicates, sorted.
+func uniqueFields(fields Fields) Fields {
+	set := make(map[string]bool, len(fields))
+	out := Fields{}
+	for _, name := range fields {
+		if !set[name] {
+			set[name] = true
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
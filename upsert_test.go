@@ -0,0 +1,136 @@
+// godoo/upsert_test.go
+package godoo
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpsertKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     Fields
+		values  map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{"single field", Fields{"ref"}, map[string]interface{}{"ref": "A-1"}, `["A-1"]`, ""},
+		{"numbers compare as ids", Fields{"code"}, map[string]interface{}{"code": 7.0}, `[7]`, ""},
+		{"many2one pair as read", Fields{"company_id", "ref"}, map[string]interface{}{"company_id": []interface{}{int64(1), "ACME"}, "ref": "A-1"}, `[1,"A-1"]`, ""},
+		{"many2one id as written", Fields{"company_id", "ref"}, map[string]interface{}{"company_id": 1, "ref": "A-1"}, `[1,"A-1"]`, ""},
+		{"many2one value", Fields{"company_id"}, map[string]interface{}{"company_id": Many2One{ID: 3, Name: "ACME"}}, `[3]`, ""},
+		{"missing field", Fields{"ref"}, map[string]interface{}{"name": "x"}, "", "key field 'ref' is missing"},
+		{"empty string", Fields{"ref"}, map[string]interface{}{"ref": ""}, "", "key field 'ref' is empty"},
+		{"false", Fields{"ref"}, map[string]interface{}{"ref": false}, "", "key field 'ref' is empty"},
+		{"nil", Fields{"ref"}, map[string]interface{}{"ref": nil}, "", "key field 'ref' is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := upsertKey(tt.key, tt.values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("upsertKey() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("upsertKey() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("upsertKey() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertDomain(t *testing.T) {
+	tests := []struct {
+		name string
+		key  Fields
+		rows []Data
+		want Domain
+	}{
+		{
+			name: "single field uses in",
+			key:  Fields{"ref"},
+			rows: []Data{{"ref": "A"}, {"ref": "B"}},
+			want: Domain{{"ref", "in", []interface{}{"A", "B"}}},
+		},
+		{
+			name: "composite key of one row",
+			key:  Fields{"company_id", "ref"},
+			rows: []Data{{"company_id": 1, "ref": "A"}},
+			want: Domain{{"&"}, {"company_id", "=", 1}, {"ref", "=", "A"}},
+		},
+		{
+			name: "composite key of two rows",
+			key:  Fields{"company_id", "ref"},
+			rows: []Data{{"company_id": 1, "ref": "A"}, {"company_id": 2, "ref": "B"}},
+			want: Domain{
+				{"|"},
+				{"&"}, {"company_id", "=", 1}, {"ref", "=", "A"},
+				{"&"}, {"company_id", "=", 2}, {"ref", "=", "B"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := upsertDomain(tt.key, tt.rows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("upsertDomain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertWhere(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	store := newFakeRecords()
+	srv.handle = store.handle
+	c := srv.client(t)
+	ctx := context.Background()
+	domain := Domain{{"ref", "=", "A-1"}}
+
+	// A slice with spare capacity: the Limit(2) of the lookup must not land in the
+	// caller's array, nor reach the create call.
+	options := make([]*Options, 1, 4)
+	options[0] = Lang("es_VE")
+	spare := options[:2]
+
+	steps := []struct {
+		data Data
+		want UpsertOutcome
+	}{
+		{Data{"ref": "A-1", "name": "Azure"}, UpsertCreated},
+		{Data{"ref": "A-1", "name": "Azure"}, UpsertUnchanged},
+		{Data{"ref": "A-1", "name": "Azure Interior"}, UpsertUpdated},
+	}
+	for _, step := range steps {
+		res, err := c.UpsertWhere(ctx, ModelResPartner, domain, step.data, options...)
+		if err != nil {
+			t.Fatalf("UpsertWhere(%v): %v", step.data, err)
+		}
+		if res.Outcome != step.want || res.ID != 1 {
+			t.Errorf("UpsertWhere(%v) = %+v, want %s of record 1", step.data, res, step.want)
+		}
+	}
+	if spare[1] != nil {
+		t.Errorf("UpsertWhere wrote %+v past the caller's options", spare[1])
+	}
+	for _, call := range srv.received() {
+		if call.Method == "create" {
+			if _, ok := call.Kwargs["limit"]; ok {
+				t.Errorf("create was called with the lookup's limit: %v", call.Kwargs)
+			}
+		}
+	}
+	if rec := store.get(1); rec["name"] != "Azure Interior" {
+		t.Errorf("stored record = %v", rec)
+	}
+
+	store.insert(map[string]interface{}{"ref": "A-1"})
+	if _, err := c.UpsertWhere(ctx, ModelResPartner, domain, Data{"name": "x"}); err == nil {
+		t.Error("UpsertWhere matching two records succeeded")
+	}
+}
@@ -0,0 +1,284 @@
+// godoo/version.go
+package godoo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ServerVersion is the parsed answer of Odoo's `common.version`.
+type ServerVersion struct {
+	Raw             string // server_version, e.g. "17.0+e" or "saas~17.2+e"
+	Series          string // server_serie, e.g. "17.0"
+	Major           int    // 17
+	Minor           int    // 0 for 17.0, 2 for saas~17.2
+	Micro           int
+	ReleaseLevel    string // "final", "alpha", "beta", "candidate"
+	Enterprise      bool
+	SaaS            bool
+	ProtocolVersion int
+}
+
+// String returns the raw server version.
+func (v *ServerVersion) String() string {
+	return v.Raw
+}
+
+// AtLeast reports whether the server is major.minor or newer.
+func (v *ServerVersion) AtLeast(major, minor int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	return v.Minor >= minor
+}
+
+// Feature names a server capability whose availability depends on the Odoo version.
+type Feature string
+
+const (
+	// FeatureNameGet: the `name_get` model method exists (removed in Odoo 17, use display_name).
+	FeatureNameGet Feature = "name_get"
+	// FeatureDisplayNameField: `display_name` can be read as a regular field on every model.
+	FeatureDisplayNameField Feature = "display_name_field"
+	// FeatureAnyOperator: domains accept the `any` / `not any` operators (Odoo 17+).
+	FeatureAnyOperator Feature = "any_operator"
+	// FeatureAllowedCompanyIDs: multi-company context uses `allowed_company_ids` (Odoo 13+)
+	// instead of `force_company`.
+	FeatureAllowedCompanyIDs Feature = "allowed_company_ids"
+	// FeatureJSON2: the /json/2/<model>/<method> endpoints are available.
+	FeatureJSON2 Feature = "json2"
+)
+
+// Supports reports whether the server version provides the given feature.
+func (v *ServerVersion) Supports(f Feature) bool {
+	switch f {
+	case FeatureNameGet:
+		return !v.AtLeast(17, 0)
+	case FeatureDisplayNameField:
+		return v.AtLeast(8, 0)
+	case FeatureAnyOperator:
+		return v.AtLeast(17, 0)
+	case FeatureAllowedCompanyIDs:
+		return v.AtLeast(13, 0)
+	case FeatureJSON2:
+		return v.AtLeast(17, 0)
+	}
+	return false
+}
+
+// parseServerVersion builds a ServerVersion from the `common.version` dictionary.
+func parseServerVersion(info map[string]interface{}) (*ServerVersion, error) {
+	v := &ServerVersion{}
+	v.Raw, _ = info["server_version"].(string)
+	v.Series, _ = info["server_serie"].(string)
+	if p, ok := toID(info["protocol_version"]); ok {
+		v.ProtocolVersion = int(p)
+	}
+
+	parts, _ := info["server_version_info"].([]interface{})
+	if len(parts) < 2 {
+		if v.Raw == "" {
+			return nil, fmt.Errorf("%w: version information missing", ErrInvalidResponse)
+		}
+		// Fall back to the textual version, e.g. "16.0" or "saas~16.3+e".
+		parts = nil
+		text := strings.TrimPrefix(strings.SplitN(v.Raw, "+", 2)[0], "saas~")
+		for _, p := range strings.SplitN(text, ".", 3) {
+			parts = append(parts, p)
+		}
+		v.SaaS = strings.HasPrefix(v.Raw, "saas~")
+	}
+
+	for i, p := range parts {
+		var n int
+		switch t := p.(type) {
+		case string:
+			if strings.HasPrefix(t, "saas~") {
+				v.SaaS = true
+				t = strings.TrimPrefix(t, "saas~")
+			}
+			n, _ = strconv.Atoi(t)
+		default:
+			id, _ := toID(t)
+			n = int(id)
+		}
+		switch i {
+		case 0:
+			v.Major = n
+		case 1:
+			v.Minor = n
+		case 2:
+			v.Micro = n
+		case 3:
+			v.ReleaseLevel, _ = p.(string)
+		case 5:
+			v.Enterprise = p == "e"
+		}
+	}
+	if strings.Contains(v.Raw, "+e") {
+		v.Enterprise = true
+	}
+	if v.Series == "" {
+		v.Series = fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	if v.Major == 0 {
+		return nil, fmt.Errorf("%w: cannot parse server version %q", ErrInvalidResponse, v.Raw)
+	}
+	return v, nil
+}
+
+// ServerVersion returns the Odoo server version. It is fetched from `common.version` right
+// after each authentication and cached with the session, so helpers can cheaply pick the
+// RPC shape for the server. The client authenticates first if needed.
+func (c *OdooClient) ServerVersion(ctx context.Context) (*ServerVersion, error) {
+	if _, _, err := c.getConnection(ctx); err != nil {
+		return nil, err
+	}
+	c.sess.mu.Lock()
+	cached := c.sess.version
+	c.sess.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	info, err := c.fetchVersionInfo(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		c.logger.Error("Failed to read Odoo server version",
+			zap.Error(err),
+			zap.String("op", "ServerVersion"),
+		)
+		return nil, err
+	}
+	version, err := parseServerVersion(info)
+	if err != nil {
+		return nil, err
+	}
+
+	c.sess.mu.Lock()
+	c.sess.version = version
+	c.sess.mu.Unlock()
+	c.logger.Info("Detected Odoo server version",
+		zap.String("version", version.Raw),
+		zap.Bool("enterprise", version.Enterprise),
+		zap.String("op", "ServerVersion"),
+	)
+	return version, nil
+}
+
+// cacheServerVersion fetches the server version into the session after authentication.
+// A failure is only logged: ServerVersion fetches it again when it is needed. Called by
+// getConnection without c.sess.mu held; the version is stored only if caller is still the
+// session's caller.
+func (c *OdooClient) cacheServerVersion(ctx context.Context, caller rpcCaller) {
+	info, err := c.fetchVersionInfo(ctx)
+	var version *ServerVersion
+	if err == nil {
+		version, err = parseServerVersion(info)
+	}
+	if err != nil {
+		c.logger.Warn("Failed to read Odoo server version after authentication",
+			zap.Error(err),
+			zap.String("op", "authenticate"),
+		)
+		return
+	}
+
+	c.sess.mu.Lock()
+	current := c.sess.caller == caller
+	if current {
+		c.sess.version = version
+	}
+	c.sess.mu.Unlock()
+	if !current {
+		return
+	}
+	c.logger.Info("Detected Odoo server version",
+		zap.String("version", version.Raw),
+		zap.Bool("enterprise", version.Enterprise),
+		zap.String("op", "authenticate"),
+	)
+}
+
+// fetchVersionInfo calls `common.version` with the transport's protocol. The web session
+// transport, whose proxies may only expose /web/*, reads the /web/webclient/version_info
+// route instead, which returns the same dictionary; so does JSON-2 when /jsonrpc fails.
+func (c *OdooClient) fetchVersionInfo(ctx context.Context) (map[string]interface{}, error) {
+	var info map[string]interface{}
+
+	switch c.transport {
+	case TransportXMLRPC:
+		commonRPCClient, err := newXMLRPCClient(c.url+"/xmlrpc/2/common", c.httpClient.Transport)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to Odoo common endpoint: %w", err)
+		}
+		if err := callXMLRPC(ctx, commonRPCClient, "version", []interface{}{}, &info, closeXMLRPC(commonRPCClient)); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, parseOdooRPCError(fmt.Errorf("failed to call common.version: %w", err))
+		}
+		return info, nil
+
+	case TransportWebSession:
+		if err := postJSONRPC(ctx, c.httpClient, c.url+"/web/webclient/version_info", map[string]interface{}{}, &info); err != nil {
+			return nil, parseOdooRPCError(fmt.Errorf("failed to call /web/webclient/version_info: %w", err))
+		}
+		return info, nil
+
+	default:
+		params := map[string]interface{}{"service": "common", "method": "version", "args": []interface{}{}}
+		err := postJSONRPC(ctx, c.httpClient, c.url+"/jsonrpc", params, &info)
+		if err != nil && c.transport == TransportJSON2 && ctx.Err() == nil {
+			info = nil
+			err = postJSONRPC(ctx, c.httpClient, c.url+"/web/webclient/version_info", map[string]interface{}{}, &info)
+		}
+		if err != nil {
+			return nil, parseOdooRPCError(fmt.Errorf("failed to call common.version: %w", err))
+		}
+		return info, nil
+	}
+}
+
+// NameGet returns the display name of each record, using `name_get` on servers that still
+// provide it and reading the `display_name` field on Odoo 17+, where name_get was removed.
+func (c *OdooClient) NameGet(ctx context.Context, model Model, ids []int64, options ...*Options) (map[int64]string, error) {
+	names := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	version, err := c.ServerVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if version.Supports(FeatureNameGet) {
+		var pairs []interface{}
+		if err := c.executeRPC(ctx, string(model), "name_get", []interface{}{ids}, c.parseOptions(options...), &pairs); err != nil {
+			return nil, err
+		}
+		for _, p := range pairs {
+			id, name := many2oneID(p)
+			names[id] = name
+		}
+		return names, nil
+	}
+
+	records, err := c.Read(ctx, model, ids, Fields{"display_name"}, options...)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		id, _ := toID(r["id"])
+		name, _ := r["display_name"].(string)
+		names[id] = name
+	}
+	return names, nil
+}
@@ -0,0 +1,60 @@
+// godoo/version_test.go
+package godoo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVersionFetchDoesNotBlockCalls(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.handle = func(call fakeCall) (interface{}, error) { return []int64{1}, nil }
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var once sync.Once
+	unblock := func() { once.Do(func() { close(release) }) }
+	t.Cleanup(unblock)
+	srv.onVersion = func() {
+		select {
+		case entered <- struct{}{}:
+			<-release
+		default:
+		}
+	}
+	c := srv.client(t)
+	ctx := context.Background()
+
+	pinged := make(chan error, 1)
+	go func() { pinged <- c.Ping(ctx) }()
+	<-entered
+
+	// The first call is still reading the version after logging in: other calls use the
+	// session meanwhile instead of waiting for it.
+	searched := make(chan error, 1)
+	go func() {
+		_, err := c.Search(ctx, ModelResPartner, nil)
+		searched <- err
+	}()
+	select {
+	case err := <-searched:
+		if err != nil {
+			t.Errorf("Search: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Search waited for the version fetch of another call")
+	}
+
+	unblock()
+	if err := <-pinged; err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	version, err := c.ServerVersion(ctx)
+	if err != nil || version.Major != 17 {
+		t.Errorf("ServerVersion = %v, %v; want 17.0", version, err)
+	}
+	if logins := srv.loginCount(); logins != 1 {
+		t.Errorf("logins = %d, want 1", logins)
+	}
+}
@@ -0,0 +1,298 @@
+// godoo/web.go
+package godoo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// webCaller implements rpcCaller through the web client routes (/web/dataset/call_kw),
+// relying on the `session_id` cookie held by the http.Client's cookie jar.
+type webCaller struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+func (w *webCaller) call(ctx context.Context, model, method string, args []interface{}, kwargs map[string]interface{}, reply interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+	if kwargs == nil {
+		kwargs = map[string]interface{}{}
+	}
+	params := map[string]interface{}{
+		"model":  model,
+		"method": method,
+		"args":   args,
+		"kwargs": kwargs,
+	}
+	// The model/method suffix is ignored by Odoo but makes access logs readable.
+	endpoint := fmt.Sprintf("%s/web/dataset/call_kw/%s/%s", w.baseURL, url.PathEscape(model), url.PathEscape(method))
+	return postJSONRPC(ctx, w.httpClient, endpoint, params, reply)
+}
+
+// close has nothing to release: the http.Client may be shared with other clients,
+// so its idle connections are only closed by OdooClient.Close.
+func (w *webCaller) close() {}
+
+// ensureCookieJar gives the client its own copy of the http.Client with a cookie jar, so the
+// web session cookie is kept without mutating a shared client such as http.DefaultClient.
+func (c *OdooClient) ensureCookieJar() error {
+	if c.httpClient.Jar != nil {
+		return nil
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return fmt.Errorf("godoo: failed to create cookie jar: %w", err)
+	}
+	hc := *c.httpClient
+	hc.Jar = jar
+	c.httpClient = &hc
+	return nil
+}
+
+// authenticateWeb logs in through /web/session/authenticate and installs a webCaller.
+// Called by authenticate with c.sess.mu held.
+func (c *OdooClient) authenticateWeb(ctx context.Context, password string) error {
+	endpoint := c.url + "/web/session/authenticate"
+	params := map[string]interface{}{
+		"db":       c.db,
+		"login":    c.username,
+		"password": password,
+	}
+
+	var session map[string]interface{}
+	if err := postJSONRPC(ctx, c.httpClient, endpoint, params, &session); err != nil {
+		c.logger.Error("Odoo web session authentication failed",
+			zap.Error(err),
+			zap.String("url", endpoint),
+			zap.String("db", c.db),
+			zap.String("username", c.username),
+			zap.String("op", "authenticate"),
+		)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: %s", ErrAuthenticationFailed, err.Error())
+	}
+
+	uid, ok := session["uid"].(int64)
+	if !ok || uid <= 0 {
+		return fmt.Errorf("%w: invalid %s for user '%s' on database '%s'", ErrAuthenticationFailed, c.credentialType, c.username, c.db)
+	}
+
+	c.sess.uid = uid
+	c.sess.caller = &webCaller{httpClient: c.httpClient, baseURL: c.url}
+	c.sess.lastAuth = time.Now()
+	c.logger.Info("Successfully authenticated with Odoo web session",
+		zap.Int64("uid", c.sess.uid),
+		zap.String("db", c.db),
+		zap.String("op", "authenticate"),
+	)
+	return nil
+}
+
+// webSession returns a client of the same server and user on TransportWebSession, for the
+// controller routes (such as reports) that other transports cannot reach. It is c itself
+// with that transport, or else a client logging in with c's password, created once per
+// session. API keys and bearer tokens cannot open web sessions.
+func (c *OdooClient) webSession() (*OdooClient, error) {
+	if c.transport == TransportWebSession {
+		return c, nil
+	}
+	if c.credentialType != CredentialPassword {
+		return nil, fmt.Errorf("godoo: this needs a web session, which cannot be opened with a %s credential; use a password", c.credentialType)
+	}
+
+	c.sess.mu.Lock()
+	defer c.sess.mu.Unlock()
+	if c.sess.web != nil {
+		return c.sess.web, nil
+	}
+	web := *c
+	web.transport = TransportWebSession
+	web.knownUID = 0
+	web.sess = &session{}
+	// The session cookie goes to a jar of its own.
+	hc := *c.httpClient
+	hc.Jar = nil
+	web.httpClient = &hc
+	if err := web.ensureCookieJar(); err != nil {
+		return nil, err
+	}
+	c.sess.web = &web
+	return &web, nil
+}
+
+// WebRequest performs an HTTP request against an Odoo controller route (e.g. "/web/content/42"
+// or "/report/pdf/account.report_invoice/7") using the client's HTTP client.
+// With TransportWebSession the request carries the authenticated `session_id` cookie,
+// logging in first if the session is missing. When Odoo redirects the request to
+// /web/login because the session expired, the client logs in again and retries once;
+// other transports get an error matching ErrSessionExpired.
+//
+// The caller must close the returned response body. Non-2xx responses are returned
+// as an error together with a nil response. The timeout set with WithTimeout runs until
+// the body is closed.
+func (c *OdooClient) WebRequest(ctx context.Context, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
+	c.logger.Debug("Performing Odoo web request",
+		zap.String("method", method),
+		zap.String("path", path),
+		zap.String("op", "WebRequest"),
+	)
+
+	ctx, cancel := c.callContext(ctx)
+	resp, err := c.webRequest(ctx, method, path, body, header)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of a response when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// webRequest performs WebRequest within ctx.
+func (c *OdooClient) webRequest(ctx context.Context, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+"/"+strings.TrimLeft(path, "/"), body)
+	if err != nil {
+		return nil, fmt.Errorf("godoo: failed to build web request: %w", err)
+	}
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	resp, caller, err := c.doWebRequest(ctx, req)
+	if errors.Is(err, ErrSessionExpired) && c.transport == TransportWebSession && (req.Body == nil || req.GetBody != nil) {
+		c.logger.Info("Odoo web session expired, re-authenticating",
+			zap.String("path", path),
+			zap.String("op", "WebRequest"),
+		)
+		c.invalidateCaller(caller)
+		// The cookie jar adds cookies to the request it sends, so the retry starts afresh.
+		retry, rerr := http.NewRequestWithContext(ctx, method, req.URL.String(), nil)
+		if rerr != nil {
+			return nil, fmt.Errorf("godoo: failed to build web request: %w", rerr)
+		}
+		retry.Header = header.Clone()
+		if retry.Header == nil {
+			retry.Header = http.Header{}
+		}
+		if req.GetBody != nil {
+			if retry.Body, rerr = req.GetBody(); rerr != nil {
+				return nil, fmt.Errorf("godoo: failed to replay web request body: %w", rerr)
+			}
+			retry.GetBody, retry.ContentLength = req.GetBody, req.ContentLength
+		}
+		resp, _, err = c.doWebRequest(ctx, retry)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		c.logger.Error("Odoo web request failed",
+			zap.Error(err),
+			zap.String("path", path),
+			zap.String("op", "WebRequest"),
+		)
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		err := fmt.Errorf("godoo: web request %s %s returned HTTP %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(raw)))
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %v", ErrRecordNotFound, err)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doWebRequest sends req with the session (logging in if needed), failing with
+// ErrSessionExpired instead of following a redirect to the login page, which Odoo
+// would answer with a 200 HTML form. It also returns the caller of the session used,
+// nil for transports other than TransportWebSession.
+func (c *OdooClient) doWebRequest(ctx context.Context, req *http.Request) (*http.Response, rpcCaller, error) {
+	var caller rpcCaller
+	if c.transport == TransportWebSession {
+		var err error
+		if _, caller, err = c.getConnection(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+	hc := *c.httpClient
+	checkRedirect := c.httpClient.CheckRedirect
+	hc.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if strings.HasSuffix(next.URL.Path, "/web/login") {
+			return fmt.Errorf("%w: redirected to %s", ErrSessionExpired, next.URL.Path)
+		}
+		if checkRedirect != nil {
+			return checkRedirect(next, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	resp, err := hc.Do(req)
+	return resp, caller, err
+}
+
+// CallRoute calls an Odoo JSON controller route (declared with `type="json"`), such as
+// "/web/dataset/resequence" or a custom module endpoint, and decodes its result into reply.
+// With TransportWebSession the call is made with the authenticated session.
+func (c *OdooClient) CallRoute(ctx context.Context, path string, params map[string]interface{}, reply interface{}) error {
+	c.logger.Debug("Performing Odoo JSON route call",
+		zap.String("path", path),
+		zap.String("op", "CallRoute"),
+	)
+
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	if c.transport == TransportWebSession {
+		if _, _, err := c.getConnection(ctx); err != nil {
+			return err
+		}
+	}
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+
+	endpoint := c.url + "/" + strings.TrimLeft(path, "/")
+	if err := postJSONRPC(ctx, c.httpClient, endpoint, params, reply); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.logger.Error("Odoo JSON route call failed",
+			zap.Error(err),
+			zap.String("path", path),
+			zap.String("op", "CallRoute"),
+		)
+		return parseOdooRPCError(fmt.Errorf("failed to call Odoo route '%s': %w", path, err))
+	}
+	return nil
+}
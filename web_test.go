@@ -0,0 +1,114 @@
+// godoo/web_test.go
+package godoo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWebRequestLogsInAgainAfterLoginRedirect(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.handleWeb("/custom/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, r.Method+" "+string(body))
+	})
+	c := srv.client(t, WithTransport(TransportWebSession))
+	ctx := context.Background()
+
+	read := func() string {
+		t.Helper()
+		resp, err := c.WebRequest(ctx, http.MethodPost, "/custom/echo", strings.NewReader("payload"), nil)
+		if err != nil {
+			t.Fatalf("WebRequest: %v", err)
+		}
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		return string(raw)
+	}
+
+	if got := read(); got != "POST payload" {
+		t.Errorf("first request = %q, want %q", got, "POST payload")
+	}
+	srv.expireSessions()
+	// The expired session is redirected to /web/login: the client logs in again and replays the body.
+	if got := read(); got != "POST payload" {
+		t.Errorf("request after expiry = %q, want %q", got, "POST payload")
+	}
+	if n := srv.loginCount(); n != 2 {
+		t.Errorf("logins = %d, want 2", n)
+	}
+}
+
+func TestWebRequestWithoutSessionReportsExpiry(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.handleWeb("/web/content/1", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "content")
+	})
+	c := srv.client(t, WithTransport(TransportJSONRPC))
+
+	_, err := c.WebRequest(context.Background(), http.MethodGet, "/web/content/1", nil, nil)
+	if !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("WebRequest error = %v, want ErrSessionExpired", err)
+	}
+	if n := srv.loginCount(); n != 0 {
+		t.Errorf("logins = %d, want 0", n)
+	}
+}
+
+func TestWebTransportRetriesExpiredSession(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.handle = func(call fakeCall) (interface{}, error) { return []int64{3}, nil }
+	c := srv.client(t, WithTransport(TransportWebSession))
+	ctx := context.Background()
+
+	if _, err := c.Search(ctx, ModelResPartner, nil); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	srv.expireSessions()
+	ids, err := c.Search(ctx, ModelResPartner, nil)
+	if err != nil {
+		t.Fatalf("Search after expiry: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != 3 {
+		t.Errorf("Search = %v, want [3]", ids)
+	}
+	if logins := srv.loginCount(); logins != 2 {
+		t.Errorf("logins = %d, want 2", logins)
+	}
+}
+
+func TestConcurrentWebRequestsLogInOnceAfterExpiry(t *testing.T) {
+	srv := newFakeOdoo(t, "s3cret")
+	srv.handleWeb("/web/content/1", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "content")
+	})
+	c := srv.client(t, WithTransport(TransportWebSession))
+	ctx := context.Background()
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	srv.expireSessions()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.WebRequest(ctx, http.MethodGet, "/web/content/1", nil, nil)
+			if err != nil {
+				t.Errorf("WebRequest after expiry: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if logins := srv.loginCount(); logins != 2 {
+		t.Errorf("logins = %d, want 2", logins)
+	}
+}
@@ -0,0 +1,41 @@
+// godoo/x2many.go
+package godoo
+
+// X2ManyCommand is one command of the list written to a one2many or many2many field,
+// e.g. Data{"tag_ids": []interface{}{X2ManyLink(3), X2ManyUnlink(5)}}.
+type X2ManyCommand = []interface{}
+
+// X2ManyCreate creates a new related record with values and links it (command 0).
+func X2ManyCreate(values Data) X2ManyCommand {
+	return X2ManyCommand{0, 0, values.ToRPC()}
+}
+
+// X2ManyUpdate writes values on the related record id (command 1).
+func X2ManyUpdate(id int64, values Data) X2ManyCommand {
+	return X2ManyCommand{1, id, values.ToRPC()}
+}
+
+// X2ManyDelete removes the related record id from the relation and deletes it (command 2).
+func X2ManyDelete(id int64) X2ManyCommand {
+	return X2ManyCommand{2, id, 0}
+}
+
+// X2ManyUnlink removes the related record id from the relation without deleting it (command 3).
+func X2ManyUnlink(id int64) X2ManyCommand {
+	return X2ManyCommand{3, id, 0}
+}
+
+// X2ManyLink adds the existing record id to the relation (command 4).
+func X2ManyLink(id int64) X2ManyCommand {
+	return X2ManyCommand{4, id, 0}
+}
+
+// X2ManyClear removes every record from the relation (command 5).
+func X2ManyClear() X2ManyCommand {
+	return X2ManyCommand{5, 0, 0}
+}
+
+// X2ManySet replaces the relation with exactly ids (command 6).
+func X2ManySet(ids []int64) X2ManyCommand {
+	return X2ManyCommand{6, 0, ids}
+}
@@ -0,0 +1,161 @@
+// godoo/xmlid.go
+package godoo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Reference is the record an external id (XML ID) points to.
+type Reference struct {
+	Model Model
+	ID    int64
+	// dataID is the id of the ir.model.data entry.
+	dataID int64
+}
+
+// splitXMLID splits "module.name" into its module and name.
+func splitXMLID(xmlid string) (module, name string, err error) {
+	module, name, ok := strings.Cut(xmlid, ".")
+	if !ok || module == "" || name == "" {
+		return "", "", fmt.Errorf("godoo: invalid external id %q, expected \"module.name\"", xmlid)
+	}
+	return module, name, nil
+}
+
+// Ref resolves an external id such as "base.main_company" to its model and record id, like
+// env.ref in Odoo. An unknown external id yields ErrRecordNotFound.
+func (c *OdooClient) Ref(ctx context.Context, xmlid string, options ...*Options) (Model, int64, error) {
+	refs, err := c.Refs(ctx, []string{xmlid}, options...)
+	if err != nil {
+		return "", 0, err
+	}
+	ref, ok := refs[xmlid]
+	if !ok {
+		return "", 0, fmt.Errorf("%w: external id '%s'", ErrRecordNotFound, xmlid)
+	}
+	return ref.Model, ref.ID, nil
+}
+
+// Refs resolves many external ids with a single ir.model.data query. Unknown external ids
+// are missing from the result.
+func (c *OdooClient) Refs(ctx context.Context, xmlids []string, options ...*Options) (map[string]Reference, error) {
+	result := make(map[string]Reference, len(xmlids))
+	if len(xmlids) == 0 {
+		return result, nil
+	}
+	modules := make([]string, 0, len(xmlids))
+	names := make([]string, 0, len(xmlids))
+	wanted := make(map[string]bool, len(xmlids))
+	for _, xmlid := range xmlids {
+		module, name, err := splitXMLID(xmlid)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, module)
+		names = append(names, name)
+		wanted[xmlid] = true
+	}
+
+	// module IN (...) AND name IN (...) may match extra pairs, which are filtered below.
+	domain := Domain{{"module", "in", modules}, {"name", "in", names}}
+	var records []map[string]interface{}
+	err := c.executeRPC(ctx, string(ModelIrModelData), "search_read", []interface{}{domain.ToRPC(), Fields{"module", "name", "model", "res_id"}.ToRPC()}, c.parseOptions(options...), &records)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		module, _ := GetString(r, "module")
+		name, _ := GetString(r, "name")
+		xmlid := module + "." + name
+		if !wanted[xmlid] {
+			continue
+		}
+		ref := Reference{}
+		model, _ := GetString(r, "model")
+		ref.Model = Model(model)
+		ref.ID, _ = GetInt64(r, "res_id")
+		ref.dataID, _ = GetInt64(r, "id")
+		result[xmlid] = ref
+	}
+
+	c.logger.Debug("Resolved Odoo external ids",
+		zap.Int("requested", len(xmlids)),
+		zap.Int("found", len(result)),
+		zap.String("op", "Refs"),
+	)
+	return result, nil
+}
+
+// UpsertByXMLID creates or updates the record of model registered under xmlid, giving
+// idempotent seeding: the first call creates the record with data and registers the
+// external id (with noupdate set, so that upgrading a module of the same name does not
+// remove it); later calls write data to the same record. created reports which one
+// happened. An external id that still points to a deleted record is re-pointed to a new
+// one; pointing to another model is an error.
+//
+// The lookup and the write are separate calls, so concurrent upserts of a new external
+// id may both create a record; ir.model.data's unique constraint rejects the second one.
+func (c *OdooClient) UpsertByXMLID(ctx context.Context, model Model, xmlid string, data Data, options ...*Options) (id int64, created bool, err error) {
+	module, name, err := splitXMLID(xmlid)
+	if err != nil {
+		return 0, false, err
+	}
+	refs, err := c.Refs(ctx, []string{xmlid}, options...)
+	if err != nil {
+		return 0, false, err
+	}
+
+	ref, found := refs[xmlid]
+	if found {
+		if ref.Model != model {
+			return 0, false, fmt.Errorf("godoo: external id '%s' points to %s, not %s", xmlid, ref.Model, model)
+		}
+		existing, err := c.Search(ctx, model, Domain{{"id", "=", ref.ID}}, ActiveTest(false))
+		if err != nil {
+			return 0, false, err
+		}
+		if len(existing) == 1 {
+			if _, err := c.Update(ctx, model, []int64{ref.ID}, data, options...); err != nil {
+				return 0, false, err
+			}
+			c.logger.Info("Odoo record updated by external id",
+				zap.String("model", string(model)),
+				zap.String("xmlid", xmlid),
+				zap.Int64("id", ref.ID),
+				zap.String("op", "UpsertByXMLID"),
+			)
+			return ref.ID, false, nil
+		}
+	}
+
+	id, err = c.CreateOne(ctx, model, data, options...)
+	if err != nil {
+		return 0, false, err
+	}
+	if found {
+		_, err = c.Update(ctx, ModelIrModelData, []int64{ref.dataID}, Data{"res_id": id})
+	} else {
+		_, err = c.CreateOne(ctx, ModelIrModelData, Data{
+			"module":   module,
+			"name":     name,
+			"model":    string(model),
+			"res_id":   id,
+			"noupdate": true,
+		})
+	}
+	if err != nil {
+		return id, true, fmt.Errorf("godoo: created %s(%d) but failed to register external id '%s': %w", model, id, xmlid, err)
+	}
+
+	c.logger.Info("Odoo record created with external id",
+		zap.String("model", string(model)),
+		zap.String("xmlid", xmlid),
+		zap.Int64("id", id),
+		zap.String("op", "UpsertByXMLID"),
+	)
+	return id, true, nil
+}